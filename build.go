@@ -4,16 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ory/dockertest/v3/docker"
 )
 
 // Build a new image.
+//   - Default options of the Pool (see WithBuildDefaults()) are applied before buildOptions.
 //   - Rewrites old image with new one if they have the same name.
 //   - Old image with the same name won't be removed, but it will lose it's name.
+//   - Build is reported to the instrumentation of the pool (see WithInstrumentation()).
 func (p Pool) Build(ctx context.Context, buildOptions ...BuildOption) (err error) {
-	options, err := ApplyBuildOptions(uuid.NewString(), buildOptions...)
+	options, err := ApplyBuildOptions(uuid.NewString(), append(slices.Clip(p.buildDefaults), buildOptions...)...)
 	if err != nil {
 		return fmt.Errorf("failed to applyBuildOptions: %w", err)
 	}
@@ -22,11 +28,13 @@ func (p Pool) Build(ctx context.Context, buildOptions ...BuildOption) (err error
 }
 
 // BuildAndGet a new image.
+//   - Default options of the Pool (see WithBuildDefaults()) are applied before buildOptions.
 //   - Rewrites old image with new one if they have the same name.
 //   - Old image with the same name won't be removed, but it will lose it's name.
 //   - Returns information about the created image.
+//   - Build is reported to the instrumentation of the pool (see WithInstrumentation()).
 func (p Pool) BuildAndGet(ctx context.Context, buildOptions ...BuildOption) (image *docker.Image, err error) {
-	options, err := ApplyBuildOptions(uuid.NewString(), buildOptions...)
+	options, err := ApplyBuildOptions(uuid.NewString(), append(slices.Clip(p.buildDefaults), buildOptions...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to applyBuildOptions: %w", err)
 	}
@@ -50,5 +58,39 @@ func (p Pool) BuildAndGet(ctx context.Context, buildOptions ...BuildOption) (ima
 }
 
 func (p Pool) buildImage(ctx context.Context, options BuildOptions) (err error) {
-	return p.Pool.Client.BuildImage(options.toDockertest(ctx)) //nolint:wrapcheck
+	ctx, end := p.instrument().Start(ctx, OperationBuild, OperationInfo{ContainerName: "", Image: options.ImageName})
+	defer func() { end(OperationResult{Err: err, ContainerID: "", Origin: ""}) }()
+
+	attrs := make([]any, 0, 4) //nolint:mnd
+	for _, attr := range []slog.Attr{
+		slog.String(logKeyImage, options.ImageName),
+		slog.String("context_dir", options.ContextDir),
+		slog.String("remote", options.Remote),
+		slog.String("dockerfile", options.Dockerfile),
+	} {
+		if attr.Value.String() != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	p.log().InfoContext(ctx, "building image", attrs...)
+	start := time.Now()
+
+	// build steps are logged line by line
+	var output *logWriter
+	if p.logger != nil {
+		output = newLogWriter(ctx, p.logger, "build output", attrs...)
+		options.OutputStream = io.MultiWriter(options.OutputStream, output)
+	}
+
+	err = p.Pool.Client.BuildImage(options.toDockertest(ctx))
+	if output != nil {
+		output.Flush()
+	}
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	p.log().InfoContext(ctx, "image built", append(attrs, slog.Duration("duration", time.Since(start)))...)
+
+	return nil
 }
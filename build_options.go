@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path"
 	"regexp"
 	"slices"
 	"strings"
@@ -17,7 +21,22 @@ const (
 	ImageLabelUUID = DefaultLabelKeyValue + ".uuid"
 )
 
-var imageNameInvalidCharsRegexp = regexp.MustCompile("[^a-zA-Z0-9_.-:]")
+const imageNameMaxLength = 255
+
+var (
+	imageNameInvalidCharsRegexp = regexp.MustCompile("[^a-zA-Z0-9_.-:]")
+	platformRegexp              = regexp.MustCompile("^[a-z0-9_]+(?:/[a-z0-9_.]+){0,2}$")
+	buildStageRegexp            = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_.-]*$")
+
+	// imageReferenceRegexp - simplified docker reference grammar: [domain[:port]/]path[:tag][@digest].
+	imageReferenceRegexp = regexp.MustCompile(
+		`^(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*` +
+			`(?::[0-9]+)?/)?` + // domain
+			`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` + // path
+			`(?::[\w][\w.-]{0,127})?` + // tag
+			`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`, // digest
+	)
+)
 
 type (
 	// BuildOptions for (Pool).Build / (Pool).BuildAndGet functions.
@@ -98,12 +117,233 @@ func WithImageName(nameParts ...string) BuildOption {
 	}
 }
 
+// WithGitContext - build image from a git repository instead of local ContextDir.
+//   - repoURL - repository url, e.g. "https://github.com/user/repo.git", "git@github.com:user/repo.git".
+//   - ref - optional branch, tag or commit to checkout (default branch is used if empty).
+//   - subdir - optional directory inside the repository to use as build context.
+//
+// Arguments are normalized to the docker remote context syntax - "url#ref:subdir".
+// The url itself must not contain the "#" fragment, use ref and subdir instead.
+//
+// Example:
+//
+//	// "https://github.com/user/repo.git#v1.0.0:docker"
+//	WithGitContext("https://github.com/user/repo.git", "v1.0.0", "docker")
+func WithGitContext(repoURL, ref, subdir string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		if !isGitURL(repoURL) {
+			return fmt.Errorf("%w: `%s` is not a git repository url", ErrInvalidOptions, repoURL)
+		}
+		if strings.Contains(repoURL, "#") {
+			return fmt.Errorf("%w: git url `%s` must not contain fragment, use ref and subdir", ErrInvalidOptions, repoURL)
+		}
+		if strings.ContainsAny(ref, "#: ") {
+			return fmt.Errorf("%w: invalid git ref `%s`", ErrInvalidOptions, ref)
+		}
+
+		if subdir != "" {
+			subdir = path.Clean(subdir)
+			if path.IsAbs(subdir) || subdir == ".." || strings.HasPrefix(subdir, "../") {
+				return fmt.Errorf("%w: git subdir `%s` must be relative to the repository root", ErrInvalidOptions, subdir)
+			}
+			if subdir == "." {
+				subdir = ""
+			}
+		}
+
+		remote := repoURL
+		if ref != "" || subdir != "" {
+			remote += "#" + ref
+		}
+		if subdir != "" {
+			remote += ":" + subdir
+		}
+
+		// set option
+		options.Remote = remote
+
+		return nil
+	}
+}
+
+// WithTarballContext - build image from tar archive (optionally compressed) instead of local ContextDir.
+// Useful as a local stand-in for a remote tarball url or for a context generated in memory.
+//   - Dockerfile path is resolved relative to the archive root.
+//   - The reader is consumed by the build and can't be reused.
+func WithTarballContext(tarball io.Reader) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		if tarball == nil {
+			return fmt.Errorf("%w: tarball reader is nil", ErrInvalidOptions)
+		}
+
+		// set option
+		options.InputStream = tarball
+
+		return nil
+	}
+}
+
+// WithBuildArg - adds build arg (ARG instruction value).
+// Rewrites value of the arg with the same name, other args are kept.
+func WithBuildArg(name, value string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		if name == "" {
+			return fmt.Errorf("%w: build arg name is required", ErrInvalidOptions)
+		}
+
+		idx := slices.IndexFunc(options.BuildArgs, func(arg docker.BuildArg) bool { return arg.Name == name })
+		if idx != -1 {
+			options.BuildArgs[idx].Value = value
+			return nil
+		}
+
+		options.BuildArgs = append(options.BuildArgs, docker.BuildArg{Name: name, Value: value})
+
+		return nil
+	}
+}
+
+// WithBuildArgs - adds build args (ARG instruction values) in the order of sorted names.
+// See [WithBuildArg].
+func WithBuildArgs(args map[string]string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		for _, name := range slices.Sorted(maps.Keys(args)) {
+			err = WithBuildArg(name, args[name])(options)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithTarget - build specified stage of multi-stage Dockerfile.
+func WithTarget(stage string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		if !buildStageRegexp.MatchString(stage) {
+			return fmt.Errorf("%w: invalid build stage name `%s`", ErrInvalidOptions, stage)
+		}
+
+		// set option
+		options.Target = stage
+
+		return nil
+	}
+}
+
+// WithPlatform - build image for platform in format "os[/arch[/variant]]".
+//
+// Example:
+//
+//	WithPlatform("linux/arm64")
+func WithPlatform(platform string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		if !platformRegexp.MatchString(platform) {
+			return fmt.Errorf("%w: invalid platform `%s`, expected format `os[/arch[/variant]]`", ErrInvalidOptions, platform)
+		}
+
+		// set option
+		options.Platform = platform
+
+		return nil
+	}
+}
+
+// WithLabels - adds labels to the image.
+// Rewrites labels with the same keys, other labels are kept.
+//   - Labels ImageLabelUUID and DefaultLabelKeyValue can't be changed - they are used to find the image
+//     after build and to remove it by Prune.
+func WithLabels(labels map[string]string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		for key := range labels {
+			switch key {
+			case "":
+				return fmt.Errorf("%w: label key is required", ErrInvalidOptions)
+			case ImageLabelUUID, DefaultLabelKeyValue:
+				return fmt.Errorf("%w: label `%s` is reserved", ErrInvalidOptions, key)
+			}
+		}
+
+		if options.Labels == nil {
+			options.Labels = make(map[string]string, len(labels))
+		}
+		maps.Copy(options.Labels, labels)
+
+		return nil
+	}
+}
+
+// WithDockerfile - use custom Dockerfile path (relative to the build context) instead of "Dockerfile".
+func WithDockerfile(dockerfilePath string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		if dockerfilePath == "" {
+			return fmt.Errorf("%w: Dockerfile path is required", ErrInvalidOptions)
+		}
+
+		// set option
+		options.Dockerfile = dockerfilePath
+
+		return nil
+	}
+}
+
+// WithContextDir - use local directory as build context.
+// Should not be used together with WithGitContext / WithTarballContext - will return `ErrOptionConflict` error.
+func WithContextDir(dir string) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("%w: failed to stat context dir: %w", ErrInvalidOptions, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%w: context dir `%s` is not a directory", ErrInvalidOptions, dir)
+		}
+
+		// set option
+		options.ContextDir = dir
+
+		return nil
+	}
+}
+
+// WithNoCache - do not use cache when building the image.
+func WithNoCache() BuildOption {
+	return func(options *BuildOptions) (err error) {
+		options.NoCache = true
+		return nil
+	}
+}
+
+// WithBuildOutput - writes build output to w (e.g. os.Stdout or t.Output()).
+// Output is written to all writers if the option is used multiple times.
+func WithBuildOutput(w io.Writer) BuildOption {
+	return func(options *BuildOptions) (err error) {
+		if w == nil {
+			return fmt.Errorf("%w: build output writer is nil", ErrInvalidOptions)
+		}
+
+		if options.OutputStream == nil || options.OutputStream == io.Discard {
+			options.OutputStream = w
+			return nil
+		}
+
+		options.OutputStream = io.MultiWriter(options.OutputStream, w)
+
+		return nil
+	}
+}
+
 // ApplyBuildOptions sets defaults and apply custom options.
 // Options aplies in order they passed.
 //
 // Each option rewrites previous value
 //
 //	ApplyBuildOptions(WithImageName("first"), WithImageName("second")) // "second"
+//
+// Except options that accumulate values - WithBuildArg, WithBuildArgs, WithLabels, WithBuildOutput
+//
+//	ApplyBuildOptions(WithBuildArg("A", "1"), WithBuildArg("B", "2")) // A=1, B=2
 func ApplyBuildOptions(uuid string, customOpts ...BuildOption) (
 	options BuildOptions, err error,
 ) {
@@ -140,12 +380,60 @@ func (o BuildOptions) getDefault(uuid string) (defaultBuildOptions BuildOptions)
 }
 
 func (o BuildOptions) validate() (err error) {
+	if o.ImageName != "" {
+		name, _, _ := strings.Cut(o.ImageName, "@")
+		if tagIdx := strings.LastIndex(name, ":"); tagIdx > strings.LastIndex(name, "/") {
+			name = name[:tagIdx]
+		}
+		if len(name) > imageNameMaxLength || !imageReferenceRegexp.MatchString(o.ImageName) {
+			return fmt.Errorf("%w: ImageName `%s` is not a valid image reference", ErrInvalidOptions, o.ImageName)
+		}
+	}
+
+	switch {
+	case o.ContextDir != "" && o.InputStream != nil:
+		return fmt.Errorf("%w: ContextDir conflicts with InputStream", ErrOptionConflict)
+	case o.Remote != "" && o.ContextDir != "":
+		return fmt.Errorf("%w: Remote conflicts with ContextDir", ErrOptionConflict)
+	case o.Remote != "" && o.InputStream != nil:
+		return fmt.Errorf("%w: Remote conflicts with InputStream", ErrOptionConflict)
+	case o.ContextDir == "" && o.InputStream == nil && o.Remote == "":
+		return fmt.Errorf("%w: one of ContextDir, InputStream or Remote is required", ErrInvalidOptions)
+	}
+
+	if o.OutputStream == nil {
+		return fmt.Errorf("%w: OutputStream is required (use io.Discard to ignore output)", ErrInvalidOptions)
+	}
+
+	if o.Memory < 0 || o.ShmSize < 0 || o.CPUShares < 0 || o.CPUQuota < 0 || o.CPUPeriod < 0 {
+		return fmt.Errorf("%w: Memory, ShmSize and CPU limits must not be negative", ErrInvalidOptions)
+	}
+
+	// -1 - unlimited swap
+	if o.Memswap != 0 && o.Memswap != -1 {
+		if o.Memory == 0 {
+			return fmt.Errorf("%w: Memswap requires Memory to be set", ErrInvalidOptions)
+		}
+		if o.Memswap < o.Memory {
+			return fmt.Errorf(
+				"%w: Memswap (%d) must be greater than or equal to Memory (%d), it's memory plus swap",
+				ErrInvalidOptions, o.Memswap, o.Memory,
+			)
+		}
+	}
+
 	return nil
 }
 
 func (o BuildOptions) toDockertest(ctx context.Context) (dockertestBuildOptions docker.BuildImageOptions) {
+	name := o.ImageName
+	if name == "" && o.Remote != "" {
+		// docker client uses Remote as image name by default - it isn't a valid reference
+		name = DefaultLabelKeyValue + ":" + o.Labels[ImageLabelUUID]
+	}
+
 	return docker.BuildImageOptions{
-		Name:                o.ImageName,
+		Name:                name,
 		Dockerfile:          o.Dockerfile,
 		NoCache:             o.NoCache,
 		CacheFrom:           o.CacheFrom,
@@ -183,3 +471,21 @@ func (o BuildOptions) toDockertest(ctx context.Context) (dockertestBuildOptions
 		Context:             ctx,
 	}
 }
+
+// isGitURL - checks that url could be used by docker as a git remote context.
+func isGitURL(rawURL string) bool {
+	// prefixes that docker treats as a git remote context regardless of the url scheme
+	for _, prefix := range []string{"git://", "git@", "github.com/"} {
+		if strings.HasPrefix(rawURL, prefix) {
+			return true
+		}
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return (parsedURL.Scheme == "http" || parsedURL.Scheme == "https" || parsedURL.Scheme == "ssh") &&
+		parsedURL.Host != "" && strings.HasSuffix(parsedURL.Path, ".git")
+}
@@ -1,8 +1,13 @@
 package tcontainer
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/ory/dockertest/v3/docker"
@@ -140,3 +145,383 @@ func Test_BuildOptions_WithImageName(t *testing.T) { //nolint:dupl // similar to
 		})
 	}
 }
+
+func Test_BuildOptions_WithGitContext(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		url    string
+		ref    string
+		subdir string
+	}
+	type want struct {
+		remote string
+		err    error
+	}
+	type testCase struct {
+		name string
+		args args
+		want want
+	}
+	testCases := []testCase{
+		{
+			name: "url_only",
+			args: args{url: "https://github.com/user/repo.git"},
+			want: want{remote: "https://github.com/user/repo.git"},
+		},
+		{
+			name: "ref",
+			args: args{url: "https://github.com/user/repo.git", ref: "v1.0.0"},
+			want: want{remote: "https://github.com/user/repo.git#v1.0.0"},
+		},
+		{
+			name: "subdir",
+			args: args{url: "git@github.com:user/repo.git", subdir: "./docker/"},
+			want: want{remote: "git@github.com:user/repo.git#:docker"},
+		},
+		{
+			name: "ref_and_subdir",
+			args: args{url: "github.com/user/repo", ref: "main", subdir: "docker/app"},
+			want: want{remote: "github.com/user/repo#main:docker/app"},
+		},
+		{
+			name: "subdir_root",
+			args: args{url: "git://example.com/repo", subdir: "."},
+			want: want{remote: "git://example.com/repo"},
+		},
+		{
+			name: "not_git_url",
+			args: args{url: "https://example.com/context.tar.gz"},
+			want: want{err: ErrInvalidOptions},
+		},
+		{
+			name: "url_with_fragment",
+			args: args{url: "https://github.com/user/repo.git#main"},
+			want: want{err: ErrInvalidOptions},
+		},
+		{
+			name: "invalid_ref",
+			args: args{url: "https://github.com/user/repo.git", ref: "main:docker"},
+			want: want{err: ErrInvalidOptions},
+		},
+		{
+			name: "subdir_outside_repo",
+			args: args{url: "https://github.com/user/repo.git", subdir: "docker/../../etc"},
+			want: want{err: ErrInvalidOptions},
+		},
+		{
+			name: "subdir_absolute",
+			args: args{url: "https://github.com/user/repo.git", subdir: "/docker"},
+			want: want{err: ErrInvalidOptions},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			require := require.New(t)
+
+			// run logic
+			opts := BuildOptions{}
+			err := WithGitContext(test.args.url, test.args.ref, test.args.subdir)(&opts)
+			require.ErrorIs(err, test.want.err)
+			require.Equal(test.want.remote, opts.Remote)
+		})
+	}
+}
+
+func Test_BuildOptions_WithTarballContext(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	pool := MustNewPool("")
+
+	// prepare context in memory
+	dockerfile, err := os.ReadFile("internal/testing/Dockerfile.test")
+	require.NoError(err)
+
+	tarball := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(tarball)
+	require.NoError(tarWriter.WriteHeader(&tar.Header{Name: "Dockerfile", Mode: 0o600, Size: int64(len(dockerfile))}))
+	_, err = tarWriter.Write(dockerfile)
+	require.NoError(err)
+	require.NoError(tarWriter.Close())
+
+	// build
+	image, err := pool.BuildAndGet(context.Background(), WithTarballContext(tarball))
+	require.NoError(err)
+	t.Cleanup(func() { require.NoError(pool.Pool.Client.RemoveImage(image.ID)) })
+
+	require.NotEmpty(image)
+
+	// nil reader
+	_, err = ApplyBuildOptions("", WithTarballContext(nil))
+	require.ErrorIs(err, ErrInvalidOptions)
+}
+
+func Test_BuildOptions_validate(t *testing.T) {
+	t.Parallel()
+
+	withContextDir := func(options *BuildOptions) (err error) {
+		options.ContextDir = "."
+		return nil
+	}
+
+	type testCase struct {
+		name string
+		opts []BuildOption
+		err  error
+	}
+	testCases := []testCase{
+		{
+			name: "ok",
+			opts: []BuildOption{withContextDir, WithImageName("Test/ok", "redis")},
+			err:  nil,
+		},
+		{
+			name: "ok/full_reference",
+			opts: []BuildOption{withContextDir, func(options *BuildOptions) (err error) {
+				options.ImageName = "localhost:5000/team/app_name:v1.0-rc.1"
+				return nil
+			}},
+			err: nil,
+		},
+		{
+			name: "ok/remote",
+			opts: []BuildOption{WithGitContext("https://github.com/user/repo.git", "", "")},
+			err:  nil,
+		},
+		{
+			name: "ok/input_stream",
+			opts: []BuildOption{WithTarballContext(strings.NewReader(""))},
+			err:  nil,
+		},
+		{
+			name: "no_context",
+			opts: []BuildOption{},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "context_dir_and_input_stream",
+			opts: []BuildOption{withContextDir, WithTarballContext(strings.NewReader(""))},
+			err:  ErrOptionConflict,
+		},
+		{
+			name: "remote_and_context_dir",
+			opts: []BuildOption{withContextDir, WithGitContext("https://github.com/user/repo.git", "", "")},
+			err:  ErrOptionConflict,
+		},
+		{
+			name: "remote_and_input_stream",
+			opts: []BuildOption{WithTarballContext(strings.NewReader("")), WithGitContext("github.com/user/repo", "", "")},
+			err:  ErrOptionConflict,
+		},
+		{
+			name: "invalid_image_name",
+			opts: []BuildOption{withContextDir, WithImageName("Test/", "redis/")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "invalid_image_name/uppercase",
+			opts: []BuildOption{withContextDir, func(options *BuildOptions) (err error) {
+				options.ImageName = "Redis"
+				return nil
+			}},
+			err: ErrInvalidOptions,
+		},
+		{
+			name: "no_output_stream",
+			opts: []BuildOption{withContextDir, func(options *BuildOptions) (err error) {
+				options.OutputStream = nil
+				return nil
+			}},
+			err: ErrInvalidOptions,
+		},
+		{
+			name: "memswap_below_memory",
+			opts: []BuildOption{withContextDir, func(options *BuildOptions) (err error) {
+				options.Memory = 64 << 20
+				options.Memswap = 32 << 20
+				return nil
+			}},
+			err: ErrInvalidOptions,
+		},
+		{
+			name: "memswap_unlimited",
+			opts: []BuildOption{withContextDir, func(options *BuildOptions) (err error) {
+				options.Memory = 64 << 20
+				options.Memswap = -1
+				return nil
+			}},
+			err: nil,
+		},
+		{
+			name: "memswap_without_memory",
+			opts: []BuildOption{withContextDir, func(options *BuildOptions) (err error) {
+				options.Memswap = 32 << 20
+				return nil
+			}},
+			err: ErrInvalidOptions,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			require := require.New(t)
+
+			_, err := ApplyBuildOptions("uuid", test.opts...)
+			require.ErrorIs(err, test.err)
+		})
+	}
+}
+
+func Test_BuildOptions_helpers(t *testing.T) {
+	t.Parallel()
+
+	output1, output2 := &bytes.Buffer{}, &bytes.Buffer{}
+
+	type testCase struct {
+		name  string
+		opts  []BuildOption
+		check func(require *require.Assertions, options BuildOptions)
+		err   error
+	}
+	testCases := []testCase{
+		{
+			name: "WithBuildArg",
+			opts: []BuildOption{WithBuildArg("A", "1"), WithBuildArg("B", "2"), WithBuildArg("A", "3")},
+			check: func(require *require.Assertions, options BuildOptions) {
+				require.Equal([]docker.BuildArg{{Name: "A", Value: "3"}, {Name: "B", Value: "2"}}, options.BuildArgs)
+			},
+		},
+		{
+			name: "WithBuildArg/empty_name",
+			opts: []BuildOption{WithBuildArg("", "1")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithBuildArgs",
+			opts: []BuildOption{WithBuildArg("C", "0"), WithBuildArgs(map[string]string{"C": "3", "B": "2", "A": "1"})},
+			check: func(require *require.Assertions, options BuildOptions) {
+				require.Equal([]docker.BuildArg{{Name: "C", Value: "3"}, {Name: "A", Value: "1"}, {Name: "B", Value: "2"}}, options.BuildArgs)
+			},
+		},
+		{
+			name: "WithTarget",
+			opts: []BuildOption{WithTarget("builder")},
+			check: func(require *require.Assertions, options BuildOptions) {
+				require.Equal("builder", options.Target)
+			},
+		},
+		{
+			name: "WithTarget/invalid",
+			opts: []BuildOption{WithTarget("")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithPlatform",
+			opts: []BuildOption{WithPlatform("linux/arm64/v8")},
+			check: func(require *require.Assertions, options BuildOptions) {
+				require.Equal("linux/arm64/v8", options.Platform)
+			},
+		},
+		{
+			name: "WithPlatform/invalid",
+			opts: []BuildOption{WithPlatform("linux/arm64/v8/extra")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithLabels",
+			opts: []BuildOption{WithLabels(map[string]string{"team": "a"}), WithLabels(map[string]string{"team": "b", "env": "test"})},
+			check: func(require *require.Assertions, options BuildOptions) {
+				require.Equal(map[string]string{
+					DefaultLabelKeyValue: DefaultLabelKeyValue,
+					ImageLabelUUID:       "uuid",
+					"team":               "b",
+					"env":                "test",
+				}, options.Labels)
+			},
+		},
+		{
+			name: "WithLabels/reserved",
+			opts: []BuildOption{WithLabels(map[string]string{ImageLabelUUID: "other"})},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithLabels/reserved_prune_label",
+			opts: []BuildOption{WithLabels(map[string]string{DefaultLabelKeyValue: "other"})},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithDockerfile",
+			opts: []BuildOption{WithDockerfile("build/Dockerfile")},
+			check: func(require *require.Assertions, options BuildOptions) {
+				require.Equal("build/Dockerfile", options.Dockerfile)
+			},
+		},
+		{
+			name: "WithContextDir/not_exists",
+			opts: []BuildOption{WithContextDir("not_exists")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithContextDir/not_dir",
+			opts: []BuildOption{WithContextDir("internal/testing/Dockerfile.test")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithNoCache",
+			opts: []BuildOption{WithNoCache()},
+			check: func(require *require.Assertions, options BuildOptions) {
+				require.True(options.NoCache)
+			},
+		},
+		{
+			name: "WithBuildOutput",
+			opts: []BuildOption{WithBuildOutput(output1), WithBuildOutput(output2)},
+			check: func(require *require.Assertions, options BuildOptions) {
+				_, err := io.WriteString(options.OutputStream, "output")
+				require.NoError(err)
+				require.Equal("output", output1.String())
+				require.Equal("output", output2.String())
+			},
+		},
+		{
+			name: "WithBuildOutput/nil",
+			opts: []BuildOption{WithBuildOutput(nil)},
+			err:  ErrInvalidOptions,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			require := require.New(t)
+
+			options, err := ApplyBuildOptions("uuid", append([]BuildOption{WithContextDir(".")}, test.opts...)...)
+			require.ErrorIs(err, test.err)
+			if test.check != nil {
+				test.check(require, options)
+			}
+		})
+	}
+}
+
+func Test_Build_WithLabels(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	pool := MustNewPool("")
+
+	image, err := buildTestImage(pool,
+		WithLabels(map[string]string{"team": "tcontainer"}),
+		WithBuildArg("UNUSED", "value"),
+		WithNoCache(),
+	)
+	require.NoError(err)
+	t.Cleanup(func() { require.NoError(pool.Pool.Client.RemoveImage(image.ID)) })
+
+	require.Equal("tcontainer", image.Config.Labels["team"])
+	require.Equal(DefaultLabelKeyValue, image.Config.Labels[DefaultLabelKeyValue])
+}
@@ -0,0 +1,143 @@
+// Package chaos - network fault injection (latency, loss, corruption, bandwidth) for test containers via `tc netem`.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ory/dockertest/v3/docker"
+
+	"github.com/kiteggrad/tcontainer"
+)
+
+const capNetAdmin = "NET_ADMIN"
+
+// ErrCommandFailed - occurs when `tc` command exits with non-zero code.
+var ErrCommandFailed = errors.New("command failed")
+
+// Fault - network fault applied to the container. Use Reset to remove it.
+type Fault struct {
+	options  Options
+	executor *tcontainer.Container // container where `tc` is executed (target or sidecar)
+	sidecar  *tcontainer.Container
+	reset    bool
+}
+
+// Inject - applies `tc netem` rules to the outgoing traffic of the running container (see (Pool).Run).
+//   - By default rules are applied by exec in the container. It requires `tc` (iproute2) in the image
+//     and NET_ADMIN capability (e.g. tcontainer.WithHostConfig with CapAdd).
+//   - Use WithSidecar(image) to apply rules from sidecar container that shares network namespace of the container.
+//   - Repeated Inject to the same container replaces previous rules.
+func Inject(ctx context.Context, container *tcontainer.Container, customOpts ...Option) (fault *Fault, err error) {
+	if container == nil {
+		return nil, fmt.Errorf("%w: container is required", tcontainer.ErrInvalidOptions)
+	}
+
+	options, err := ApplyOptions(customOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ApplyOptions: %w", err)
+	}
+
+	fault = &Fault{
+		options:  options,
+		executor: container,
+		sidecar:  nil,
+		reset:    false,
+	}
+
+	if options.SidecarImage != "" {
+		fault.sidecar, err = runSidecar(ctx, container, options.SidecarImage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to runSidecar: %w", err)
+		}
+		fault.executor = fault.sidecar
+	}
+
+	err = fault.exec(ctx, options.netemArgs())
+	if err != nil {
+		if fault.sidecar != nil {
+			_ = fault.sidecar.Terminate(ctx)
+		}
+
+		return nil, fmt.Errorf("failed to apply netem rules: %w", err)
+	}
+
+	return fault, nil
+}
+
+// Reset - removes applied rules (and sidecar container). Repeated calls do nothing.
+func (f *Fault) Reset(ctx context.Context) (err error) {
+	if f.reset {
+		return nil
+	}
+
+	err = f.exec(ctx, []string{"tc", "qdisc", "del", "dev", f.options.Interface, "root"})
+	if err != nil {
+		return fmt.Errorf("failed to remove netem rules: %w", err)
+	}
+
+	if f.sidecar != nil {
+		err = f.sidecar.Terminate(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to Terminate sidecar: %w", err)
+		}
+	}
+
+	f.reset = true
+
+	return nil
+}
+
+func (f *Fault) exec(ctx context.Context, cmd []string) (err error) {
+	result, err := f.executor.Exec(ctx, cmd...)
+	if err != nil {
+		return fmt.Errorf("failed to Exec: %w", err)
+	}
+
+	if result.ExitCode != 0 {
+		return fmt.Errorf(
+			"%w: `%s` exit code %d: %s",
+			ErrCommandFailed, strings.Join(cmd, " "), result.ExitCode, strings.TrimSpace(result.Stderr+result.Stdout),
+		)
+	}
+
+	return nil
+}
+
+// runSidecar - runs container from the local image in the network namespace of the target container.
+//   - Sidecar has no expiry, it's removed by Fault.Reset or (Pool).Prune.
+//   - Defaults of the target Pool (networks, port bindings, reuse, expiry, ...) aren't applied to the sidecar.
+func runSidecar(
+	ctx context.Context, target *tcontainer.Container, image string,
+) (sidecar *tcontainer.Container, err error) {
+	pool := target.Pool().WithoutDefaults()
+
+	// works offline - image must be built or loaded before
+	_, err = pool.Pool.Client.InspectImage(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to InspectImage `%s` (sidecar image must exist locally): %w", image, err)
+	}
+
+	repository, tag := docker.ParseRepositoryTag(image)
+	opts := []tcontainer.RunOption{
+		// sidecar lives until Fault.Reset, expiry would remove it with the applied rules
+		tcontainer.WithExpiry(0),
+		tcontainer.WithNetworkMode("container:" + target.Container.ID),
+		tcontainer.WithEntrypoint("tail", "-f", "/dev/null"),
+		tcontainer.WithHostConfig(func(hostConfig *docker.HostConfig) {
+			hostConfig.CapAdd = append(hostConfig.CapAdd, capNetAdmin)
+		}),
+	}
+	if tag != "" {
+		opts = append(opts, tcontainer.WithTag(tag))
+	}
+
+	sidecar, err = pool.Run(ctx, repository, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to Run: %w", err)
+	}
+
+	return sidecar, nil
+}
@@ -0,0 +1,256 @@
+package chaos
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiteggrad/tcontainer"
+	"github.com/kiteggrad/tcontainer/dockerfake"
+)
+
+const netemImage = "tcontainer-netem:test"
+
+// buildNetemImage - builds local image with `tc` so tests don't depend on third party images with iproute2.
+//   - Each test gets own tag, the tag is removed on test cleanup.
+//   - Skips the test if the image can't be built (base image and iproute2 are downloaded from the network).
+func buildNetemImage(t *testing.T, pool tcontainer.Pool) (image string) {
+	t.Helper()
+
+	repository, _ := docker.ParseRepositoryTag(netemImage)
+	image = repository + ":" + strings.ReplaceAll(t.Name(), "/", "-")
+
+	err := pool.Build(
+		context.Background(),
+		tcontainer.WithImageName(image),
+		tcontainer.WithDockerfile("internal/testing/Dockerfile.netem"),
+		tcontainer.WithContextDir(".."),
+	)
+	if err != nil {
+		t.Skip("failed to build netem image, network is unavailable? ", err)
+	}
+	t.Cleanup(func() {
+		_ = pool.Pool.Client.RemoveImageExtended(image, docker.RemoveImageOptions{Force: true})
+	})
+
+	return image
+}
+
+// qdiscShow - returns `tc qdisc show` output from the container.
+func qdiscShow(t *testing.T, container *tcontainer.Container) string {
+	t.Helper()
+
+	result, err := container.Exec(context.Background(), "tc", "qdisc", "show", "dev", defaultInterface)
+	require.NoError(t, err)
+	require.Zero(t, result.ExitCode, result.Stderr)
+
+	return result.Stdout
+}
+
+func Test_Inject(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := tcontainer.PoolForTest(t)
+	repository, tag := docker.ParseRepositoryTag(buildNetemImage(t, pool))
+	container, err := pool.Run(
+		context.Background(), repository,
+		tcontainer.WithTag(tag),
+		tcontainer.WithCmd("tail", "-f", "/dev/null"),
+		tcontainer.WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.CapAdd = []string{capNetAdmin} }),
+	)
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	fault, err := Inject(
+		context.Background(), container,
+		WithDelay(100*time.Millisecond, 10*time.Millisecond), WithLoss(1.5), WithRate(1_000_000),
+	)
+	require.NoError(err)
+
+	qdisc := qdiscShow(t, container)
+	require.Contains(qdisc, "netem")
+	require.Contains(qdisc, "delay 100ms")
+	require.Contains(qdisc, "loss 1.5%")
+	require.Contains(qdisc, "rate 1Mbit")
+
+	// replace rules
+	fault, err = Inject(context.Background(), container, WithCorruption(5))
+	require.NoError(err)
+	qdisc = qdiscShow(t, container)
+	require.Contains(qdisc, "corrupt 5%")
+	require.NotContains(qdisc, "delay")
+
+	// reset
+	require.NoError(fault.Reset(context.Background()))
+	require.NotContains(qdiscShow(t, container), "netem")
+	require.NoError(fault.Reset(context.Background()))
+}
+
+func Test_Inject_WithoutNetAdmin(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := tcontainer.PoolForTest(t)
+	repository, tag := docker.ParseRepositoryTag(buildNetemImage(t, pool))
+	container, err := pool.Run(context.Background(), repository, tcontainer.WithTag(tag), tcontainer.WithCmd("tail", "-f", "/dev/null"))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	_, err = Inject(context.Background(), container, WithLoss(10))
+	require.ErrorIs(err, ErrCommandFailed)
+}
+
+func Test_Inject_WithSidecar(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := tcontainer.PoolForTest(t)
+	image := buildNetemImage(t, pool)
+
+	// target without tc and NET_ADMIN
+	container, err := pool.Run(context.Background(), "busybox", tcontainer.WithCmd("tail", "-f", "/dev/null"))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	fault, err := Inject(context.Background(), container, WithDelay(time.Second, 0), WithSidecar(image))
+	require.NoError(err)
+	require.NotNil(fault.sidecar)
+	require.Contains(qdiscShow(t, fault.sidecar), "delay 1s")
+
+	// reset removes sidecar
+	require.NoError(fault.Reset(context.Background()))
+	_, err = pool.Pool.Client.InspectContainer(fault.sidecar.Container.ID)
+	var noSuchContainerErr *docker.NoSuchContainer
+	require.ErrorAs(err, &noSuchContainerErr)
+
+	// sidecar image is never pulled
+	_, err = Inject(context.Background(), container, WithLoss(1), WithSidecar("tcontainer-missing:image"))
+	require.Error(err)
+	require.ErrorIs(err, docker.ErrNoSuchImage)
+}
+
+func Test_Inject_WithSidecar_AfterExpiry(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	server := dockerfake.NewServer()
+	t.Cleanup(server.Close)
+	server.AddImage(netemImage, nil)
+	server.SetExecHandler(func(docker.Container, []string) dockerfake.ExecResult {
+		return dockerfake.ExecResult{ExitCode: 0, Stdout: "", Stderr: ""}
+	})
+
+	basePool := tcontainer.NewPoolWithClient(server.Client())
+	network, err := basePool.CreateNetwork(context.Background())
+	require.NoError(err)
+
+	// defaults conflict with network mode of the sidecar
+	const expiry = time.Second
+	pool := basePool.WithDefaults(
+		tcontainer.WithExpiry(expiry),
+		tcontainer.WithNetwork(network),
+		tcontainer.WithRandomHostPort("80"),
+	)
+
+	container, err := pool.Run(context.Background(), "busybox", tcontainer.WithExpiry(0))
+	require.NoError(err)
+
+	fault, err := Inject(context.Background(), container, WithLoss(1), WithSidecar(netemImage))
+	require.NoError(err)
+
+	// sidecar isn't removed by the default expiry of the pool
+	require.NotContains(fault.sidecar.Options().Networks, network)
+	time.Sleep(expiry * 2)
+	require.NoError(fault.Reset(context.Background()))
+	_, ok := server.Container(fault.sidecar.Container.Name)
+	require.False(ok)
+}
+
+func Test_ApplyOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		opts        []Option
+		expectedCmd []string
+		err         error
+	}{
+		{
+			name:        "delay",
+			opts:        []Option{WithDelay(100*time.Millisecond, 0)},
+			expectedCmd: []string{"tc", "qdisc", "replace", "dev", "eth0", "root", "netem", "delay", "100000us"},
+		},
+		{
+			name: "all",
+			opts: []Option{
+				WithInterface("eth1"),
+				WithDelay(time.Second, time.Millisecond),
+				WithLoss(0.5),
+				WithCorruption(100),
+				WithRate(8000),
+			},
+			expectedCmd: []string{
+				"tc", "qdisc", "replace", "dev", "eth1", "root", "netem",
+				"delay", "1000000us", "1000us", "loss", "0.5%", "corrupt", "100%", "rate", "8000bit",
+			},
+		},
+		{
+			name: "no_rules",
+			opts: nil,
+			err:  tcontainer.ErrInvalidOptions,
+		},
+		{
+			name: "jitter_without_delay",
+			opts: []Option{WithDelay(0, time.Millisecond)},
+			err:  tcontainer.ErrInvalidOptions,
+		},
+		{
+			name: "negative_delay",
+			opts: []Option{WithDelay(-time.Millisecond, 0)},
+			err:  tcontainer.ErrInvalidOptions,
+		},
+		{
+			name: "loss_out_of_range",
+			opts: []Option{WithLoss(101)},
+			err:  tcontainer.ErrInvalidOptions,
+		},
+		{
+			name: "corruption_out_of_range",
+			opts: []Option{WithCorruption(-1)},
+			err:  tcontainer.ErrInvalidOptions,
+		},
+		{
+			name: "empty_interface",
+			opts: []Option{WithLoss(1), WithInterface("")},
+			err:  tcontainer.ErrInvalidOptions,
+		},
+		{
+			name: "empty_sidecar_image",
+			opts: []Option{WithLoss(1), WithSidecar("")},
+			err:  tcontainer.ErrInvalidOptions,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			options, err := ApplyOptions(tc.opts...)
+			if tc.err != nil {
+				require.ErrorIs(err, tc.err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.expectedCmd, options.netemArgs())
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package chaos_mocks
+
+import (
+	chaos "github.com/kiteggrad/tcontainer/chaos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Option is an autogenerated mock type for the Option type
+type Option struct {
+	mock.Mock
+}
+
+type Option_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Option) EXPECT() *Option_Expecter {
+	return &Option_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *Option) Execute(options *chaos.Options) error {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*chaos.Options) error); ok {
+		r0 = rf(options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Option_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type Option_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *chaos.Options
+func (_e *Option_Expecter) Execute(options interface{}) *Option_Execute_Call {
+	return &Option_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *Option_Execute_Call) Run(run func(options *chaos.Options)) *Option_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*chaos.Options))
+	})
+	return _c
+}
+
+func (_c *Option_Execute_Call) Return(err error) *Option_Execute_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Option_Execute_Call) RunAndReturn(run func(*chaos.Options) error) *Option_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOption creates a new instance of Option. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Option {
+	mock := &Option{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
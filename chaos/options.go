@@ -0,0 +1,186 @@
+package chaos
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kiteggrad/tcontainer"
+)
+
+const (
+	defaultInterface = "eth0"
+	maxPercent       = 100
+)
+
+type (
+	// Options - network fault options. All rules are applied together by one `tc netem` qdisc.
+	Options struct {
+		// Interface - network interface inside the container ("eth0" by default).
+		Interface string
+		// Delay - added delay of outgoing packets.
+		Delay time.Duration
+		// Jitter - random variation of the Delay.
+		Jitter time.Duration
+		// Loss - percent of dropped outgoing packets (0-100).
+		Loss float64
+		// Corrupt - percent of corrupted outgoing packets (0-100).
+		Corrupt float64
+		// Rate - outgoing bandwidth limit in bits per second.
+		Rate uint64
+		// SidecarImage - apply rules from sidecar container (with NET_ADMIN capability)
+		// that shares network namespace of the target container.
+		//   - Image must exist locally, it will not be pulled.
+		//   - Use it when target container has no `tc` or NET_ADMIN capability.
+		SidecarImage string
+	}
+
+	// Option - option for Inject.
+	Option func(options *Options) (err error)
+)
+
+// WithInterface - use network interface (e.g. "eth1" for the second network of the container).
+func WithInterface(name string) Option {
+	return func(options *Options) (err error) {
+		if name == "" {
+			return fmt.Errorf("%w: interface name is required", tcontainer.ErrInvalidOptions)
+		}
+
+		options.Interface = name
+
+		return nil
+	}
+}
+
+// WithDelay - add delay with jitter to outgoing packets.
+func WithDelay(delay, jitter time.Duration) Option {
+	return func(options *Options) (err error) {
+		options.Delay = delay
+		options.Jitter = jitter
+
+		return nil
+	}
+}
+
+// WithLoss - drop percent (0-100) of outgoing packets.
+func WithLoss(percent float64) Option {
+	return func(options *Options) (err error) {
+		options.Loss = percent
+		return nil
+	}
+}
+
+// WithCorruption - corrupt percent (0-100) of outgoing packets.
+func WithCorruption(percent float64) Option {
+	return func(options *Options) (err error) {
+		options.Corrupt = percent
+		return nil
+	}
+}
+
+// WithRate - limit outgoing bandwidth in bits per second.
+func WithRate(bitsPerSecond uint64) Option {
+	return func(options *Options) (err error) {
+		options.Rate = bitsPerSecond
+		return nil
+	}
+}
+
+// WithSidecar - apply rules from sidecar container instead of exec in the target container.
+//   - image - local image with `tc` (iproute2), it will not be pulled.
+func WithSidecar(image string) Option {
+	return func(options *Options) (err error) {
+		if image == "" {
+			return fmt.Errorf("%w: sidecar image is required", tcontainer.ErrInvalidOptions)
+		}
+
+		options.SidecarImage = image
+
+		return nil
+	}
+}
+
+// ApplyOptions - apply custom options to the default ones.
+func ApplyOptions(customOpts ...Option) (options Options, err error) {
+	options = options.getDefault()
+
+	for _, opt := range customOpts {
+		err = opt(&options)
+		if err != nil {
+			return Options{}, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+
+	err = options.validate()
+	if err != nil {
+		return Options{}, fmt.Errorf("failed to validate: %w", err)
+	}
+
+	return options, nil
+}
+
+func (o Options) getDefault() (defaultOptions Options) {
+	return Options{
+		Interface:    defaultInterface,
+		Delay:        0,
+		Jitter:       0,
+		Loss:         0,
+		Corrupt:      0,
+		Rate:         0,
+		SidecarImage: "",
+	}
+}
+
+func (o Options) validate() (err error) {
+	if o.Interface == "" {
+		return fmt.Errorf("%w: Interface is required", tcontainer.ErrInvalidOptions)
+	}
+	if o.Delay < 0 || o.Jitter < 0 {
+		return fmt.Errorf("%w: Delay and Jitter can't be negative", tcontainer.ErrInvalidOptions)
+	}
+	if o.Jitter > 0 && o.Delay == 0 {
+		return fmt.Errorf("%w: Jitter requires Delay", tcontainer.ErrInvalidOptions)
+	}
+	if o.Loss < 0 || o.Loss > maxPercent {
+		return fmt.Errorf("%w: Loss must be in range 0-100", tcontainer.ErrInvalidOptions)
+	}
+	if o.Corrupt < 0 || o.Corrupt > maxPercent {
+		return fmt.Errorf("%w: Corrupt must be in range 0-100", tcontainer.ErrInvalidOptions)
+	}
+	if o.Delay == 0 && o.Loss == 0 && o.Corrupt == 0 && o.Rate == 0 {
+		return fmt.Errorf("%w: at least one rule (delay, loss, corruption, rate) is required", tcontainer.ErrInvalidOptions)
+	}
+
+	return nil
+}
+
+// netemArgs - returns `tc qdisc replace` command with netem rules.
+func (o Options) netemArgs() (cmd []string) {
+	cmd = []string{"tc", "qdisc", "replace", "dev", o.Interface, "root", "netem"}
+
+	if o.Delay > 0 {
+		cmd = append(cmd, "delay", formatDuration(o.Delay))
+		if o.Jitter > 0 {
+			cmd = append(cmd, formatDuration(o.Jitter))
+		}
+	}
+	if o.Loss > 0 {
+		cmd = append(cmd, "loss", formatPercent(o.Loss))
+	}
+	if o.Corrupt > 0 {
+		cmd = append(cmd, "corrupt", formatPercent(o.Corrupt))
+	}
+	if o.Rate > 0 {
+		cmd = append(cmd, "rate", strconv.FormatUint(o.Rate, 10)+"bit")
+	}
+
+	return cmd
+}
+
+func formatDuration(d time.Duration) string {
+	return strconv.FormatInt(d.Microseconds(), 10) + "us"
+}
+
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
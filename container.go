@@ -0,0 +1,312 @@
+package tcontainer
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	// ContainerOriginCreated - new container was created.
+	ContainerOriginCreated ContainerOrigin = "created"
+	// ContainerOriginReused - existing container was reused (see WithReuse).
+	ContainerOriginReused ContainerOrigin = "reused"
+	// ContainerOriginRepaired - existing container was repaired (unpaused or started) and reused (see WithReuse).
+	ContainerOriginRepaired ContainerOrigin = "repaired"
+	// ContainerOriginRecreated - existing container was removed and created again (see WithRemoveOnExists, WithReuse).
+	ContainerOriginRecreated ContainerOrigin = "recreated"
+)
+
+// ErrPortNotExposed - occurs when the container doesn't expose requested port.
+var ErrPortNotExposed = errors.New("port is not exposed")
+
+type (
+	// Container - running test container returned by (Pool).Run.
+	//   - Embedded *dockertest.Resource is available for compatibility (e.g. container.Container.ID).
+	//   - Close and Terminate run BeforeTerminate and AfterTerminate hooks (see WithHooks).
+	Container struct {
+		*dockertest.Resource
+
+		pool         Pool
+		options      RunOptions
+		outcome      RunOutcome
+		startupStats StartupStats
+		// volumes - named volumes created with the container, removed by Terminate (see WithVolume)
+		volumes []string
+	}
+
+	// ContainerOrigin - how (Pool).Run got the container.
+	ContainerOrigin string
+
+	// RunOutcome - result of the (Pool).Run path, e.g. to decide whether to run migrations or seeding
+	// (not needed for reused or repaired containers).
+	RunOutcome struct {
+		Origin ContainerOrigin
+		// ReuseErr - error of the reuse attempt that caused recreation (see ReuseContainerOptions.RecreateOnErr).
+		// Nil for other origins and for recreation by WithRemoveOnExists.
+		ReuseErr error
+		// RestoredFromSnapshot - container runs from the snapshot image (see WithRestoreFromSnapshot).
+		RestoredFromSnapshot bool
+	}
+
+	// ExecResult - result of the command executed by (*Container).Exec.
+	ExecResult struct {
+		ExitCode int
+		Stdout   string
+		Stderr   string
+	}
+)
+
+// Pool - returns the pool that runs the container.
+func (c *Container) Pool() Pool {
+	return c.pool
+}
+
+// Options - returns effective options the container was run with (including BeforeCreate hooks changes).
+func (c *Container) Options() RunOptions {
+	return c.options
+}
+
+// Outcome - returns how (Pool).Run got the container (created, reused, repaired or recreated).
+func (c *Container) Outcome() RunOutcome {
+	return c.outcome
+}
+
+// State - returns the last known state of the container (see Inspect to refresh it).
+func (c *Container) State() docker.State {
+	return c.Container.State
+}
+
+// Inspect - refreshes and returns the container state and configuration.
+func (c *Container) Inspect(ctx context.Context) (container *docker.Container, err error) {
+	err = c.pool.refreshContainer(ctx, c.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Container, nil
+}
+
+// Endpoint - returns endpoint to connect to the private port of the container (see GetAPIEndpoints).
+//   - port - private port with or without protocol (e.g. "80" or "80/tcp").
+func (c *Container) Endpoint(port PrivatePort) (endpoint APIEndpoint, err error) {
+	endpoint, ok := GetAPIEndpoints(c.Resource)[strings.TrimSuffix(port, "/tcp")]
+	if !ok {
+		return APIEndpoint{}, fmt.Errorf("%w: `%s`", ErrPortNotExposed, port)
+	}
+
+	return endpoint, nil
+}
+
+// Exec - runs command in the container and waits for it to finish.
+// Non-zero exit code is not an error, check result.ExitCode.
+func (c *Container) Exec(ctx context.Context, cmd ...string) (result ExecResult, err error) {
+	if len(cmd) == 0 {
+		return ExecResult{}, fmt.Errorf("%w: command is required", ErrInvalidOptions)
+	}
+
+	exec, err := c.pool.Pool.Client.CreateExec(docker.CreateExecOptions{ //nolint:exhaustruct
+		Cmd:          cmd,
+		Container:    c.Container.ID,
+		AttachStdout: true,
+		AttachStderr: true,
+		Context:      ctx,
+	})
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("failed to CreateExec: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = c.pool.Pool.Client.StartExec(exec.ID, docker.StartExecOptions{ //nolint:exhaustruct
+		OutputStream: &stdout,
+		ErrorStream:  &stderr,
+		Context:      ctx,
+	})
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("failed to StartExec: %w", err)
+	}
+
+	inspect, err := c.pool.Pool.Client.InspectExec(exec.ID)
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("failed to InspectExec: %w", err)
+	}
+
+	return ExecResult{
+		ExitCode: inspect.ExitCode,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+	}, nil
+}
+
+// Logs - writes all container logs to stdout and stderr writers (nil writer discards the stream).
+func (c *Container) Logs(ctx context.Context, stdout, stderr io.Writer) (err error) {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	err = c.pool.Pool.Client.Logs(docker.LogsOptions{ //nolint:exhaustruct
+		Context:      ctx,
+		Container:    c.Container.ID,
+		OutputStream: stdout,
+		ErrorStream:  stderr,
+		Stdout:       true,
+		Stderr:       true,
+		RawTerminal:  c.Container.Config != nil && c.Container.Config.Tty,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Logs: %w", err)
+	}
+
+	return nil
+}
+
+// CopyTo - writes content to the file in the container (parent directory must exist).
+//   - containerPath - absolute path of the file.
+//   - mode - file permissions (e.g. 0o644).
+func (c *Container) CopyTo(ctx context.Context, containerPath string, content []byte, mode int64) (err error) {
+	if !path.IsAbs(containerPath) {
+		return fmt.Errorf("%w: container path `%s` must be absolute", ErrInvalidOptions, containerPath)
+	}
+
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+	err = tarWriter.WriteHeader(&tar.Header{ //nolint:exhaustruct
+		Typeflag: tar.TypeReg,
+		Name:     path.Base(containerPath),
+		Mode:     mode,
+		Size:     int64(len(content)),
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to WriteHeader: %w", err)
+	}
+	_, err = tarWriter.Write(content)
+	if err != nil {
+		return fmt.Errorf("failed to Write: %w", err)
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return fmt.Errorf("failed to Close tar writer: %w", err)
+	}
+
+	err = c.pool.Pool.Client.UploadToContainer(c.Container.ID, docker.UploadToContainerOptions{
+		InputStream:          &archive,
+		Path:                 path.Dir(containerPath),
+		NoOverwriteDirNonDir: false,
+		Context:              ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to UploadToContainer: %w", err)
+	}
+
+	return nil
+}
+
+// CopyFrom - returns content of the file from the container.
+//   - containerPath - absolute path of the regular file.
+func (c *Container) CopyFrom(ctx context.Context, containerPath string) (content []byte, err error) {
+	if !path.IsAbs(containerPath) {
+		return nil, fmt.Errorf("%w: container path `%s` must be absolute", ErrInvalidOptions, containerPath)
+	}
+
+	var archive bytes.Buffer
+	err = c.pool.Pool.Client.DownloadFromContainer(c.Container.ID, docker.DownloadFromContainerOptions{
+		OutputStream:      &archive,
+		Path:              containerPath,
+		InactivityTimeout: 0,
+		Context:           ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to DownloadFromContainer: %w", err)
+	}
+
+	tarReader := tar.NewReader(&archive)
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tar header: %w", err)
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("%w: `%s` is not a regular file", ErrInvalidOptions, containerPath)
+	}
+
+	content, err = io.ReadAll(tarReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from tar: %w", err)
+	}
+
+	return content, nil
+}
+
+// Stop - see (Pool).Stop.
+func (c *Container) Stop(ctx context.Context, customOpts ...LifecycleOption) (err error) {
+	return c.pool.Stop(ctx, c, customOpts...)
+}
+
+// Start - see (Pool).Start.
+func (c *Container) Start(ctx context.Context, customOpts ...LifecycleOption) (err error) {
+	return c.pool.Start(ctx, c, customOpts...)
+}
+
+// Restart - see (Pool).Restart.
+func (c *Container) Restart(ctx context.Context, customOpts ...LifecycleOption) (err error) {
+	return c.pool.Restart(ctx, c, customOpts...)
+}
+
+// Pause - see (Pool).Pause.
+func (c *Container) Pause(ctx context.Context, customOpts ...LifecycleOption) (err error) {
+	return c.pool.Pause(ctx, c, customOpts...)
+}
+
+// Unpause - see (Pool).Unpause.
+func (c *Container) Unpause(ctx context.Context, customOpts ...LifecycleOption) (err error) {
+	return c.pool.Unpause(ctx, c, customOpts...)
+}
+
+// Kill - see (Pool).Kill.
+func (c *Container) Kill(ctx context.Context, customOpts ...LifecycleOption) (err error) {
+	return c.pool.Kill(ctx, c, customOpts...)
+}
+
+// Terminate - removes the container with its anonymous volumes and named volumes created with it (see WithVolume),
+// runs BeforeTerminate hooks before and AfterTerminate hooks after removal (see WithHooks).
+//   - The container is removed even if BeforeTerminate hook fails, all errors are returned.
+//   - Already removed container (e.g. by AutoRemove after Stop) isn't an error.
+func (c *Container) Terminate(ctx context.Context) (err error) {
+	return c.pool.terminate(ctx, c)
+}
+
+// Close - Terminate with background context, overrides (*dockertest.Resource).Close to run hooks.
+func (c *Container) Close() (err error) {
+	return c.Terminate(context.Background())
+}
+
+// Expire - stops the container after the timeout in background (see WithExpiry),
+// overrides (*dockertest.Resource).Expire.
+func (c *Container) Expire(seconds uint) (err error) {
+	c.pool.expire(context.Background(), c.Container.ID, seconds)
+
+	return nil
+}
+
+// ConnectToNetwork - Connect with background context, overrides (*dockertest.Resource).ConnectToNetwork.
+func (c *Container) ConnectToNetwork(network *dockertest.Network) (err error) {
+	return c.pool.Connect(context.Background(), c, network)
+}
+
+// DisconnectFromNetwork - Disconnect with background context,
+// overrides (*dockertest.Resource).DisconnectFromNetwork.
+func (c *Container) DisconnectFromNetwork(network *dockertest.Network) (err error) {
+	return c.pool.Disconnect(context.Background(), c, network)
+}
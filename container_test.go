@@ -0,0 +1,117 @@
+package tcontainer
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Container(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var terminated atomic.Int32
+	terminateHook := func(context.Context, *Container) (err error) {
+		terminated.Add(1)
+		return nil
+	}
+
+	pool, container, err := runBusybox(
+		context.Background(),
+		WithContainerName(t.Name()),
+		WithEnv("KEY", "value"),
+		WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.AutoRemove = false }),
+		WithHooks(Hooks{BeforeTerminate: []Hook{terminateHook}, AfterTerminate: []Hook{terminateHook}}),
+	)
+	require.NoError(err)
+
+	// options and origin
+	require.Equal(pool, container.Pool())
+	require.Equal(formatContainerName(t.Name()), container.Options().Name)
+	require.Contains(container.Options().Env, "KEY=value")
+	require.Equal(RunOutcome{Origin: ContainerOriginCreated, ReuseErr: nil}, container.Outcome())
+	require.True(container.State().Running)
+
+	// endpoint
+	endpoint, err := container.Endpoint(containerAPIPort)
+	require.NoError(err)
+	require.Equal(GetAPIEndpoints(container.Resource)[containerAPIPort], endpoint)
+	endpoint, err = container.Endpoint(containerAPIPort + "/tcp")
+	require.NoError(err)
+	require.NotEmpty(endpoint.Port)
+	_, err = container.Endpoint("9999")
+	require.ErrorIs(err, ErrPortNotExposed)
+
+	// exec
+	result, err := container.Exec(context.Background(), "sh", "-c", "echo out && echo err >&2 && exit 3")
+	require.NoError(err)
+	require.Equal(ExecResult{ExitCode: 3, Stdout: "out\n", Stderr: "err\n"}, result)
+	_, err = container.Exec(context.Background())
+	require.ErrorIs(err, ErrInvalidOptions)
+
+	// copy
+	err = container.CopyTo(context.Background(), "/tmp/file.txt", []byte("content"), 0o644)
+	require.NoError(err)
+	result, err = container.Exec(context.Background(), "cat", "/tmp/file.txt")
+	require.NoError(err)
+	require.Equal("content", result.Stdout)
+	content, err := container.CopyFrom(context.Background(), "/tmp/file.txt")
+	require.NoError(err)
+	require.Equal([]byte("content"), content)
+	_, err = container.CopyFrom(context.Background(), "/tmp")
+	require.ErrorIs(err, ErrInvalidOptions)
+	require.ErrorIs(container.CopyTo(context.Background(), "file.txt", nil, 0o644), ErrInvalidOptions)
+
+	// logs
+	var stdout bytes.Buffer
+	err = container.Logs(context.Background(), &stdout, nil)
+	require.NoError(err)
+
+	// lifecycle reruns Retry.Operation from the options
+	require.NoError(container.Stop(context.Background(), WithStopTimeout(0)))
+	require.False(container.State().Running)
+	require.NoError(container.Start(context.Background()))
+	require.True(container.State().Running)
+	require.NoError(pingBusyboxContainerServer(context.Background(), container))
+
+	// inspect
+	inspected, err := container.Inspect(context.Background())
+	require.NoError(err)
+	require.Equal(container.Container.ID, inspected.ID)
+	require.True(inspected.State.Running)
+
+	// reuse
+	reusedContainer, err := pool.Run(context.Background(), "busybox", WithContainerName(t.Name()), WithReuse(false))
+	require.NoError(err)
+	require.Equal(RunOutcome{Origin: ContainerOriginReused, ReuseErr: nil}, reusedContainer.Outcome())
+	require.Equal(container.Container.ID, reusedContainer.Container.ID)
+
+	// close runs terminate hooks
+	require.NoError(container.Close())
+	require.Equal(int32(2), terminated.Load())
+	_, err = pool.Pool.Client.InspectContainer(container.Container.ID)
+	var noSuchContainerErr *docker.NoSuchContainer
+	require.ErrorAs(err, &noSuchContainerErr)
+}
+
+func Test_Container_Recreated(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	_, container, err := runBusybox(context.Background(), WithContainerName(t.Name()))
+	require.NoError(err)
+	t.Cleanup(func() { _ = container.Close() })
+
+	_, recreatedContainer, err := runBusybox(context.Background(), WithContainerName(t.Name()), WithRemoveOnExists())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(recreatedContainer.Close()) })
+
+	require.Equal(RunOutcome{Origin: ContainerOriginRecreated, ReuseErr: nil}, recreatedContainer.Outcome())
+	require.NotEqual(container.Container.ID, recreatedContainer.Container.ID)
+}
@@ -0,0 +1,719 @@
+package dockerfake
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	statusCreated = "created"
+	statusRunning = "running"
+	statusPaused  = "paused"
+	statusExited  = "exited"
+
+	defaultStopTimeout = 10 * time.Second
+	signalExitCodeBase = 128
+	fakePID            = 4242
+	defaultHostIP      = "0.0.0.0"
+	networkModeDefault = "default"
+	networkModePrefix  = "container:"
+	mountTypeVolume    = "volume"
+)
+
+var (
+	containerNameRegexp = regexp.MustCompile(`^/?[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	signalByName = map[string]int{
+		"SIGHUP": 1, "SIGINT": 2, "SIGQUIT": 3, "SIGKILL": 9, "SIGUSR1": 10, "SIGUSR2": 12,
+		"SIGTERM": 15, "SIGCHLD": 17, "SIGCONT": 18, "SIGSTOP": 19, "SIGURG": 23, "SIGWINCH": 28,
+	}
+	// signals ignored by processes by default - container doesn't stop on them (see ContainerExpiry of tcontainer).
+	ignoredSignals = []int{17, 18, 23, 28}
+)
+
+func (s *Server) handleContainerCreate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		docker.Config
+		HostConfig       *docker.HostConfig
+		NetworkingConfig *docker.NetworkingConfig
+	}
+	err := decodeBody(r, &body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Query().Get("name"), "/")
+	if name != "" && !containerNameRegexp.MatchString(name) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid container name (%s)", name))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing := s.findContainerByName(name); existing != nil {
+		writeError(w, http.StatusConflict, fmt.Sprintf(
+			"Conflict. The container name %q is already in use by container %q. "+
+				"You have to remove (or rename) that container to be able to reuse that name.",
+			existing.Name, existing.ID,
+		))
+		return
+	}
+
+	image := s.findImage(body.Image)
+	if image == nil {
+		writeError(w, http.StatusNotFound, "No such image: "+body.Image)
+		return
+	}
+
+	id := newID()
+	if name == "" {
+		name = "dockerfake_" + shortID(id)
+	}
+
+	config := body.Config
+	config.Labels = mergeLabels(image.Config.Labels, config.Labels)
+
+	hostConfig := body.HostConfig
+	if hostConfig == nil {
+		hostConfig = &docker.HostConfig{} //nolint:exhaustruct
+	}
+
+	// API < 1.44 doesn't support more than one network at creation
+	if body.NetworkingConfig != nil && len(body.NetworkingConfig.EndpointsConfig) > 1 {
+		writeError(w, http.StatusBadRequest, "Container cannot be connected to network endpoints: "+
+			strings.Join(slices.Sorted(maps.Keys(body.NetworkingConfig.EndpointsConfig)), ", "))
+		return
+	}
+
+	endpoints, err := s.containerEndpoints(id, hostConfig.NetworkMode, body.NetworkingConfig)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	created := &container{
+		Container: docker.Container{ //nolint:exhaustruct
+			ID:      id,
+			Created: time.Now(),
+			Config:  &config,
+			State: docker.State{ //nolint:exhaustruct
+				Status: statusCreated,
+			},
+			Image:           image.ID,
+			NetworkSettings: &docker.NetworkSettings{Networks: endpoints}, //nolint:exhaustruct
+			Name:            "/" + name,
+			Driver:          "overlay2",
+			HostConfig:      hostConfig,
+		},
+		seq:     s.nextSeq(),
+		volumes: s.containerVolumes(hostConfig),
+		logs:    nil,
+	}
+	created.Path, created.Args = command(config)
+	s.containers[id] = created
+
+	writeJSON(w, http.StatusCreated, map[string]any{"Id": id, "Warnings": []string{}})
+}
+
+func (s *Server) handleContainerList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filters, err := parseFilters(query.Get("filters"), "name", "id", "label", "status")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	all := isTrue(query.Get("all"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	containers := []docker.APIContainers{}
+	for _, container := range slices.Backward(s.sortedContainers()) {
+		matched := (all || container.State.Running) &&
+			filters.matchAny("name", func(filter string) bool {
+				return matchRegexp(filter, strings.TrimPrefix(container.Name, "/"))
+			}) &&
+			filters.matchAny("id", func(filter string) bool { return strings.HasPrefix(container.ID, filter) }) &&
+			filters.matchAny("status", func(filter string) bool { return container.State.Status == filter }) &&
+			filters.matchLabels(container.Config.Labels)
+		if !matched {
+			continue
+		}
+
+		containers = append(containers, docker.APIContainers{ //nolint:exhaustruct
+			ID:       container.ID,
+			Image:    container.Config.Image,
+			Command:  strings.Join(append([]string{container.Path}, container.Args...), " "),
+			Created:  container.Created.Unix(),
+			State:    container.State.Status,
+			Status:   container.State.String(),
+			Ports:    container.NetworkSettings.PortMappingAPI(),
+			Names:    []string{container.Name},
+			Labels:   container.Config.Labels,
+			Networks: docker.NetworkList{Networks: container.NetworkSettings.Networks},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, containers)
+}
+
+func (s *Server) handleContainerInspect(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	if container == nil {
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, container.Container)
+}
+
+func (s *Server) handleContainerStart(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	if container == nil {
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return
+	}
+
+	if container.State.Running {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	err := s.start(container)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleContainerStop(w http.ResponseWriter, r *http.Request) {
+	found, ok := s.stop(w, r, true)
+	if !ok {
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleContainerRestart(w http.ResponseWriter, r *http.Request) {
+	_, ok := s.stop(w, r, false)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	if container == nil {
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return
+	}
+
+	err := s.start(container)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleContainerKill(w http.ResponseWriter, r *http.Request) {
+	signal, err := parseSignal(r.URL.Query().Get("signal"), signalByName["SIGKILL"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	if container == nil {
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return
+	}
+
+	if !container.State.Running {
+		writeError(w, http.StatusConflict, fmt.Sprintf(
+			"Cannot kill container: %s: Container %s is not running", r.PathValue("id"), container.ID,
+		))
+		return
+	}
+
+	if !slices.Contains(ignoredSignals, signal) {
+		s.exit(container, signalExitCodeBase+signal, true)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleContainerPause(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	switch {
+	case container == nil:
+		writeNoSuchContainer(w, r.PathValue("id"))
+	case !container.State.Running:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is not running", container.ID))
+	case container.State.Paused:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is already paused", container.ID))
+	default:
+		container.State.Paused = true
+		container.State.Status = statusPaused
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleContainerUnpause(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	switch {
+	case container == nil:
+		writeNoSuchContainer(w, r.PathValue("id"))
+	case !container.State.Paused:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is not paused", container.ID))
+	default:
+		container.State.Paused = false
+		container.State.Status = statusRunning
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleContainerRemove(w http.ResponseWriter, r *http.Request) {
+	force := isTrue(r.URL.Query().Get("force"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	if container == nil {
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return
+	}
+
+	if container.State.Running && !force {
+		writeError(w, http.StatusConflict, fmt.Sprintf(
+			"You cannot remove a running container %s. Stop the container before attempting removal or force remove",
+			container.ID,
+		))
+		return
+	}
+
+	s.remove(container)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// stop - stops container by the request (stop or restart) with timeout, writes error response if it isn't ok.
+// Found is false if container isn't running.
+func (s *Server) stop(w http.ResponseWriter, r *http.Request, autoRemove bool) (found, ok bool) {
+	query := r.URL.Query()
+
+	timeout := defaultStopTimeout
+	if value := query.Get("t"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid value for t: %s", value))
+			return false, false
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	s.mu.Lock()
+	container := s.findContainer(r.PathValue("id"))
+	if container == nil {
+		s.mu.Unlock()
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return false, false
+	}
+	if !container.State.Running {
+		s.mu.Unlock()
+		return false, true
+	}
+
+	id := container.ID
+	stopSignal := query.Get("signal")
+	if stopSignal == "" {
+		stopSignal = container.Config.StopSignal
+	}
+	s.mu.Unlock()
+
+	signal, err := parseSignal(stopSignal, signalByName["SIGTERM"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false, false
+	}
+
+	exitCode := 0
+	if slices.Contains(ignoredSignals, signal) {
+		// the process ignores stop signal - docker kills it after timeout
+		s.wait(r, timeout)
+		exitCode = signalExitCodeBase + signalByName["SIGKILL"]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stopped, exists := s.containers[id]
+	if exists && stopped.State.Running {
+		s.exit(stopped, exitCode, autoRemove)
+	}
+
+	return true, true
+}
+
+// start - starts created or exited container. Requires s.mu.
+func (s *Server) start(container *container) (err error) {
+	ports, err := s.allocatePorts(container)
+	if err != nil {
+		return err
+	}
+	container.NetworkSettings.Ports = ports
+
+	for name, endpoint := range container.NetworkSettings.Networks {
+		network := s.networks[endpoint.NetworkID]
+		if network == nil {
+			continue
+		}
+		s.attach(container, network, name)
+	}
+
+	container.State = docker.State{ //nolint:exhaustruct
+		Status:    statusRunning,
+		Running:   true,
+		Pid:       fakePID,
+		StartedAt: time.Now(),
+	}
+
+	return nil
+}
+
+// exit - marks container as exited and removes it if AutoRemove is enabled. Requires s.mu.
+func (s *Server) exit(container *container, exitCode int, autoRemove bool) {
+	container.State.Running = false
+	container.State.Paused = false
+	container.State.Restarting = false
+	container.State.Status = statusExited
+	container.State.Pid = 0
+	container.State.ExitCode = exitCode
+	container.State.FinishedAt = time.Now()
+	container.NetworkSettings.Ports = nil
+
+	for name, endpoint := range container.NetworkSettings.Networks {
+		if network := s.networks[endpoint.NetworkID]; network != nil {
+			delete(network.Containers, container.ID)
+		}
+		endpoint.IPAddress = ""
+		endpoint.Gateway = ""
+		endpoint.IPPrefixLen = 0
+		container.NetworkSettings.Networks[name] = endpoint
+	}
+	container.NetworkSettings.IPAddress = ""
+	container.NetworkSettings.Gateway = ""
+
+	if autoRemove && container.HostConfig.AutoRemove {
+		s.remove(container)
+	}
+}
+
+// remove - removes container with its execs. Requires s.mu.
+func (s *Server) remove(container *container) {
+	for _, endpoint := range container.NetworkSettings.Networks {
+		if network := s.networks[endpoint.NetworkID]; network != nil {
+			delete(network.Containers, container.ID)
+		}
+	}
+	for id, exec := range s.execs {
+		if exec.ContainerID == container.ID {
+			delete(s.execs, id)
+		}
+	}
+
+	delete(s.containers, container.ID)
+}
+
+// attach - assigns ip address of the network to the running container. Requires s.mu.
+func (s *Server) attach(container *container, network *network, name string) {
+	endpoint := container.NetworkSettings.Networks[name]
+	if !network.hasAddresses() {
+		return
+	}
+
+	endpoint.IPAddress = network.allocateIP()
+	endpoint.Gateway = network.gateway()
+	endpoint.IPPrefixLen = 16
+	container.NetworkSettings.Networks[name] = endpoint
+
+	network.Containers[container.ID] = docker.Endpoint{ //nolint:exhaustruct
+		Name:        strings.TrimPrefix(container.Name, "/"),
+		ID:          endpoint.EndpointID,
+		IPv4Address: endpoint.IPAddress + "/16",
+	}
+
+	if network.Name == bridgeNetwork {
+		container.NetworkSettings.IPAddress = endpoint.IPAddress
+		container.NetworkSettings.Gateway = endpoint.Gateway
+		container.NetworkSettings.IPPrefixLen = endpoint.IPPrefixLen
+	}
+}
+
+// allocatePorts - binds host ports by HostConfig.PortBindings (random port for empty or "0" host port).
+// Requires s.mu.
+func (s *Server) allocatePorts(container *container) (ports map[docker.Port][]docker.PortBinding, err error) {
+	ports = map[docker.Port][]docker.PortBinding{}
+	for port := range container.Config.ExposedPorts {
+		ports[normalizePort(port)] = nil
+	}
+
+	bindings := container.HostConfig.PortBindings
+	if container.HostConfig.PublishAllPorts {
+		bindings = make(map[docker.Port][]docker.PortBinding, len(container.Config.ExposedPorts))
+		for port := range container.Config.ExposedPorts {
+			bindings[port] = []docker.PortBinding{{HostIP: "", HostPort: ""}}
+		}
+		for port, portBindings := range container.HostConfig.PortBindings {
+			bindings[port] = portBindings
+		}
+	}
+
+	for port, portBindings := range bindings {
+		for _, binding := range portBindings {
+			if binding.HostIP == "" {
+				binding.HostIP = defaultHostIP
+			}
+
+			switch {
+			case binding.HostPort == "" || binding.HostPort == "0":
+				binding.HostPort = strconv.Itoa(s.nextFreePort())
+			case s.isPortAllocated(binding.HostPort):
+				return nil, fmt.Errorf(
+					"driver failed programming external connectivity on endpoint %s (%s): "+
+						"Bind for %s:%s failed: port is already allocated",
+					strings.TrimPrefix(container.Name, "/"), container.ID, binding.HostIP, binding.HostPort,
+				)
+			}
+
+			ports[normalizePort(port)] = append(ports[normalizePort(port)], binding)
+		}
+	}
+
+	return ports, nil
+}
+
+// nextFreePort - returns next host port that isn't used by running containers. Requires s.mu.
+func (s *Server) nextFreePort() int {
+	for s.isPortAllocated(strconv.Itoa(s.nextPort)) {
+		s.nextPort++
+	}
+	s.nextPort++
+
+	return s.nextPort - 1
+}
+
+// isPortAllocated - checks that host port is used by running container. Requires s.mu.
+func (s *Server) isPortAllocated(hostPort string) bool {
+	for _, container := range s.containers {
+		if !container.State.Running {
+			continue
+		}
+		for _, bindings := range container.NetworkSettings.Ports {
+			for _, binding := range bindings {
+				if binding.HostPort == hostPort {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// containerEndpoints - returns endpoints of networks that the new container joins. Requires s.mu.
+func (s *Server) containerEndpoints(
+	containerID, networkMode string, networkingConfig *docker.NetworkingConfig,
+) (endpoints map[string]docker.ContainerNetwork, err error) {
+	endpoints = map[string]docker.ContainerNetwork{}
+
+	configs := map[string]*docker.EndpointConfig{}
+	if networkingConfig != nil {
+		configs = networkingConfig.EndpointsConfig
+	}
+
+	switch {
+	case strings.HasPrefix(networkMode, networkModePrefix):
+		// shares network namespace of other container
+		return endpoints, nil
+	case networkMode == "" || networkMode == networkModeDefault:
+		if len(configs) == 0 {
+			configs = map[string]*docker.EndpointConfig{bridgeNetwork: nil}
+		}
+	default:
+		if _, ok := configs[networkMode]; !ok {
+			configs[networkMode] = nil
+		}
+	}
+
+	for idOrName, config := range configs {
+		network := s.findNetwork(idOrName)
+		if network == nil {
+			return nil, fmt.Errorf("network %s not found", idOrName)
+		}
+
+		var aliases []string
+		if config != nil {
+			aliases = config.Aliases
+		}
+		endpoints[network.Name] = newEndpoint(containerID, network, aliases)
+	}
+
+	return endpoints, nil
+}
+
+// containerVolumes - creates named volumes used by the container, returns their names. Requires s.mu.
+func (s *Server) containerVolumes(hostConfig *docker.HostConfig) (volumes []string) {
+	for _, mount := range hostConfig.Mounts {
+		if mount.Type != mountTypeVolume || mount.Source == "" {
+			continue
+		}
+
+		var labels map[string]string
+		if mount.VolumeOptions != nil {
+			labels = mount.VolumeOptions.Labels
+		}
+		s.addVolume(mount.Source, labels)
+		volumes = append(volumes, mount.Source)
+	}
+
+	for _, bind := range hostConfig.Binds {
+		source, _, _ := strings.Cut(bind, ":")
+		if strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") {
+			continue
+		}
+
+		s.addVolume(source, nil)
+		volumes = append(volumes, source)
+	}
+
+	return volumes
+}
+
+// findContainerByName - finds container by exact name. Requires s.mu.
+func (s *Server) findContainerByName(name string) *container {
+	if name == "" {
+		return nil
+	}
+
+	for _, container := range s.containers {
+		if container.Name == "/"+name {
+			return container
+		}
+	}
+
+	return nil
+}
+
+// newEndpoint - returns endpoint of the container in the network (user defined networks resolve short container id).
+func newEndpoint(containerID string, network *network, aliases []string) docker.ContainerNetwork {
+	aliases = slices.Clone(aliases)
+	if !network.isPredefined() {
+		aliases = append(aliases, shortID(containerID))
+	}
+
+	return docker.ContainerNetwork{ //nolint:exhaustruct
+		Aliases:    aliases,
+		NetworkID:  network.ID,
+		EndpointID: newID(),
+	}
+}
+
+// command - returns path and args of the container process.
+func command(config docker.Config) (path string, args []string) {
+	cmd := slices.Concat(config.Entrypoint, config.Cmd)
+	if len(cmd) == 0 {
+		return "", nil
+	}
+
+	return cmd[0], cmd[1:]
+}
+
+func mergeLabels(imageLabels, containerLabels map[string]string) map[string]string {
+	if len(imageLabels) == 0 {
+		return containerLabels
+	}
+
+	labels := make(map[string]string, len(imageLabels)+len(containerLabels))
+	for key, value := range imageLabels {
+		labels[key] = value
+	}
+	for key, value := range containerLabels {
+		labels[key] = value
+	}
+
+	return labels
+}
+
+// parseSignal - parses signal by number ("9") or name ("SIGKILL", "KILL").
+func parseSignal(value string, defaultSignal int) (signal int, err error) {
+	if value == "" {
+		return defaultSignal, nil
+	}
+
+	signal, err = strconv.Atoi(value)
+	if err == nil {
+		return signal, nil
+	}
+
+	signal, ok := signalByName["SIG"+strings.TrimPrefix(strings.ToUpper(value), "SIG")]
+	if !ok {
+		return 0, fmt.Errorf("invalid signal: %s", value)
+	}
+
+	return signal, nil
+}
+
+func normalizePort(port docker.Port) docker.Port {
+	if strings.Contains(string(port), "/") {
+		return port
+	}
+
+	return port + "/tcp"
+}
+
+func isTrue(value string) bool {
+	enabled, err := strconv.ParseBool(value)
+
+	return err == nil && enabled
+}
+
+func writeNoSuchContainer(w http.ResponseWriter, id string) {
+	writeError(w, http.StatusNotFound, "No such container: "+id)
+}
@@ -0,0 +1,881 @@
+package dockerfake_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiteggrad/tcontainer"
+	"github.com/kiteggrad/tcontainer/dockerfake"
+)
+
+func newPool(t *testing.T) (server *dockerfake.Server, pool tcontainer.Pool) {
+	t.Helper()
+
+	server = dockerfake.NewServer()
+	t.Cleanup(server.Close)
+
+	return server, tcontainer.NewPoolWithClient(server.Client())
+}
+
+func Test_Server_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+	server.SetExecHandler(func(_ docker.Container, cmd []string) dockerfake.ExecResult {
+		return dockerfake.ExecResult{ExitCode: 3, Stdout: "out:" + cmd[0], Stderr: "err"}
+	})
+
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName(t.Name()), tcontainer.WithRandomHostPort("80"))
+	require.NoError(err)
+	require.Equal(tcontainer.ContainerOriginCreated, container.Outcome().Origin)
+	require.True(container.State().Running)
+
+	// image is "pulled" from the fake registry
+	image, ok := server.Image("busybox:latest")
+	require.True(ok)
+	require.Equal(image.ID, container.Container.Image)
+
+	// random host port is assigned
+	endpoint, err := container.Endpoint("80")
+	require.NoError(err)
+	require.NotEmpty(endpoint.Port)
+	require.NotEqual("0", endpoint.Port)
+
+	// exec result is scripted
+	result, err := container.Exec(ctx, "echo", "hello")
+	require.NoError(err)
+	require.Equal(tcontainer.ExecResult{ExitCode: 3, Stdout: "out:echo", Stderr: "err"}, result)
+
+	// logs are scripted
+	require.NoError(server.AppendLogs(t.Name(), "stdout logs", "stderr logs"))
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.NoError(container.Logs(ctx, stdout, stderr))
+	require.Equal("stdout logs", stdout.String())
+	require.Equal("stderr logs", stderr.String())
+
+	// terminate removes the container
+	require.NoError(container.Terminate(ctx))
+	_, ok = server.Container(t.Name())
+	require.False(ok)
+}
+
+func Test_Server_Run_Reuse(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name           string
+		recreateOnErr  bool
+		update         func(container *docker.Container)
+		expectedOrigin tcontainer.ContainerOrigin
+		expectedErr    error `exhaustruct:"optional"`
+	}
+	testCases := []testCase{
+		{
+			name:           "Running",
+			update:         func(*docker.Container) {},
+			expectedOrigin: tcontainer.ContainerOriginReused,
+		},
+		{
+			name: "Paused",
+			update: func(container *docker.Container) {
+				container.State.Paused = true
+				container.State.Status = "paused"
+			},
+			expectedOrigin: tcontainer.ContainerOriginRepaired,
+		},
+		{
+			name: "OOMKilled exited container is started again",
+			update: func(container *docker.Container) {
+				container.State.Running = false
+				container.State.OOMKilled = true
+				container.State.Status = "exited"
+				container.State.ExitCode = 137
+			},
+			expectedOrigin: tcontainer.ContainerOriginRepaired,
+		},
+		{
+			name: "Dead",
+			update: func(container *docker.Container) {
+				container.State.Running = false
+				container.State.Dead = true
+				container.State.Status = "dead"
+			},
+			expectedErr: tcontainer.ErrUnreusableState,
+		},
+		{
+			name:          "Dead with RecreateOnErr",
+			recreateOnErr: true,
+			update: func(container *docker.Container) {
+				container.State.Running = false
+				container.State.Dead = true
+				container.State.Status = "dead"
+			},
+			expectedOrigin: tcontainer.ContainerOriginRecreated,
+		},
+		{
+			name: "RemovalInProgress",
+			update: func(container *docker.Container) {
+				container.State.Running = false
+				container.State.RemovalInProgress = true
+				container.State.Status = "removing"
+			},
+			expectedErr: tcontainer.ErrUnreusableState,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+			ctx := context.Background()
+
+			server, pool := newPool(t)
+
+			container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("reused"))
+			require.NoError(err)
+			require.NoError(server.UpdateContainer("reused", test.update))
+
+			reused, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("reused"), tcontainer.WithReuse(test.recreateOnErr))
+			if test.expectedErr != nil {
+				require.ErrorIs(err, test.expectedErr)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.expectedOrigin, reused.Outcome().Origin)
+			require.True(reused.State().Running)
+
+			if test.expectedOrigin == tcontainer.ContainerOriginRecreated {
+				require.NotEqual(container.Container.ID, reused.Container.ID)
+				require.ErrorIs(reused.Outcome().ReuseErr, tcontainer.ErrUnreusableState)
+			} else {
+				require.Equal(container.Container.ID, reused.Container.ID)
+			}
+		})
+	}
+}
+
+func Test_Server_Run_Reuse_Canceled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	server, pool := newPool(t)
+
+	container, err := pool.Run(context.Background(), "busybox", tcontainer.WithContainerName("reused"))
+	require.NoError(err)
+
+	// hung lookup of the existing container
+	server.Fail(dockerfake.Failure{Method: http.MethodGet, Path: `^/containers/json$`, Delay: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+
+	start := time.Now()
+	_, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("reused"), tcontainer.WithReuse(false))
+	require.ErrorIs(err, context.DeadlineExceeded)
+	require.Less(time.Since(start), time.Second)
+
+	// existing container is kept
+	containers := server.Containers()
+	require.Len(containers, 1)
+	require.Equal(container.Container.ID, containers[0].ID)
+}
+
+func Test_Server_Run_PortInUse(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+
+	_, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("first"), tcontainer.WithPortBinding("80", "18080"))
+	require.NoError(err)
+
+	_, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("second"), tcontainer.WithPortBinding("80", "18080"))
+	require.ErrorIs(err, tcontainer.ErrPortInUse)
+
+	// never started container is removed
+	_, ok := server.Container("second")
+	require.False(ok)
+}
+
+func Test_Server_Run_Volumes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+	server.AddVolume("shared", nil)
+
+	// volume created with the container is removed with it, existing volume is kept
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"),
+		tcontainer.WithVolume("data", "/data"), tcontainer.WithVolume("shared", "/shared"))
+	require.NoError(err)
+	require.Equal([]string{"data", "shared"}, volumeNames(server.Volumes()))
+	require.NoError(container.Close())
+	require.Equal([]string{"shared"}, volumeNames(server.Volumes()))
+
+	// volume of the container run for reuse is kept
+	container, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("reused"),
+		tcontainer.WithVolume("reused-data", "/data"), tcontainer.WithReuse(false))
+	require.NoError(err)
+	require.NoError(container.Close())
+	require.Equal([]string{"reused-data", "shared"}, volumeNames(server.Volumes()))
+
+	// volume of the container that failed to start is removed
+	server.Fail(dockerfake.Failure{
+		Method:  http.MethodPost,
+		Path:    `^/containers/[^/]+/start$`,
+		Status:  http.StatusInternalServerError,
+		Message: "no space left on device",
+		Times:   1,
+	})
+	_, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("failed"), tcontainer.WithVolume("failed-data", "/data"))
+	require.ErrorContains(err, "no space left on device")
+	require.Equal([]string{"reused-data", "shared"}, volumeNames(server.Volumes()))
+}
+
+func Test_Server_Lifecycle(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+
+	// stop by the stop signal of the image doesn't wait StopTimeout,
+	// container with default AutoRemove is removed by Stop
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("auto_remove"))
+	require.NoError(err)
+	stopStartedAt := time.Now()
+	require.NoError(container.Stop(ctx, tcontainer.WithStopTimeout(time.Minute)))
+	require.Less(time.Since(stopStartedAt), time.Second)
+	require.False(container.State().Running)
+	_, ok := server.Container("auto_remove")
+	require.False(ok)
+
+	container, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("auto_remove_kill"))
+	require.NoError(err)
+	require.NoError(container.Kill(ctx))
+	require.False(container.State().Running)
+	_, ok = server.Container("auto_remove_kill")
+	require.False(ok)
+
+	container, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"),
+		tcontainer.WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.AutoRemove = false }))
+	require.NoError(err)
+
+	require.NoError(container.Stop(ctx, tcontainer.WithStopTimeout(0)))
+	require.False(container.State().Running)
+	require.NoError(container.Start(ctx))
+	require.True(container.State().Running)
+	require.NoError(container.Restart(ctx, tcontainer.WithStopTimeout(0)))
+	require.True(container.State().Running)
+
+	// calls are interrupted by ctx
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(container.Pause(canceledCtx), context.Canceled)
+	require.ErrorIs(container.Restart(canceledCtx), context.Canceled)
+
+	require.NoError(container.Pause(ctx))
+	require.True(container.State().Paused)
+	require.NoError(container.Unpause(ctx))
+	require.False(container.State().Paused)
+}
+
+func Test_Server_Resource(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+	server.SetExecHandler(func(_ docker.Container, cmd []string) dockerfake.ExecResult {
+		return dockerfake.ExecResult{ExitCode: 0, Stdout: "out:" + cmd[0], Stderr: ""}
+	})
+
+	// embedded Resource is bound to the pool
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("resource"), tcontainer.WithRandomHostPort("80"))
+	require.NoError(err)
+	require.NotEmpty(container.GetPort("80/tcp"))
+
+	stdout := &bytes.Buffer{}
+	exitCode, err := container.Resource.Exec([]string{"echo"}, dockertest.ExecOptions{StdOut: stdout}) //nolint:exhaustruct
+	require.NoError(err)
+	require.Zero(exitCode)
+	require.Equal("out:echo", stdout.String())
+
+	require.NoError(container.Resource.Close())
+	_, ok := server.Container("resource")
+	require.False(ok)
+}
+
+func Test_Server_Expiry(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+
+	const expiry = time.Second
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("expiring"), tcontainer.WithExpiry(expiry))
+	require.NoError(err)
+
+	// the container ignores the stop signal of the expiry until the timeout
+	time.Sleep(expiry / 2)
+	_, ok := server.Container("expiring")
+	require.True(ok)
+
+	require.Eventually(func() bool {
+		_, ok := server.Container("expiring")
+		return !ok
+	}, expiry*3, expiry/10)
+	require.Contains(server.Requests(), dockerfake.Request{
+		Method: http.MethodPost, Path: "/containers/" + container.Container.ID + "/stop", APIVersion: "1.42",
+	})
+}
+
+func Test_Server_Fail(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+	server.AddImage("busybox", nil)
+
+	server.Fail(dockerfake.Failure{
+		Method:  http.MethodPost,
+		Path:    `^/containers/[^/]+/start$`,
+		Status:  http.StatusInternalServerError,
+		Message: "no space left on device",
+		Times:   1,
+	})
+
+	_, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName(t.Name()))
+	require.ErrorContains(err, "no space left on device")
+
+	// failure is applied only once
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName(t.Name()))
+	require.NoError(err)
+	require.True(container.State().Running)
+
+	starts := 0
+	for _, request := range server.Requests() {
+		if request.Method == http.MethodPost && request.Path == "/containers/"+container.Container.ID+"/start" {
+			starts++
+		}
+	}
+	require.Equal(1, starts)
+}
+
+func Test_Server_Prune(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		failure     dockerfake.Failure
+		expectedErr string `exhaustruct:"optional"`
+	}
+	testCases := []testCase{
+		{
+			name:        "partial failure is returned",
+			failure:     dockerfake.Failure{Method: http.MethodDelete, Path: `^/containers/`, Times: 1},
+			expectedErr: "failed to pruneContainers: failed to RemoveContainer",
+		},
+		{
+			name:        "list failure is returned",
+			failure:     dockerfake.Failure{Method: http.MethodGet, Path: `^/volumes$`},
+			expectedErr: "failed to pruneVolumes: failed to ListVolumes",
+		},
+		{
+			name:    "already removed container is ignored",
+			failure: dockerfake.Failure{Method: http.MethodDelete, Path: `^/containers/`, Status: http.StatusNotFound},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+			assert := assert.New(t)
+			ctx := context.Background()
+
+			server, pool := newPool(t)
+			labels := map[string]string{tcontainer.DefaultLabelKeyValue: tcontainer.DefaultLabelKeyValue}
+
+			for _, name := range []string{"first", "second"} {
+				_, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName(name))
+				require.NoError(err)
+			}
+			_, err := pool.CreateNetwork(ctx)
+			require.NoError(err)
+			server.AddVolume("pruned", labels)
+			server.AddVolume("side", nil)
+			server.AddImage("tcontainer/pruned", labels)
+
+			server.Fail(test.failure)
+			err = pool.Prune(ctx)
+			if test.expectedErr != "" {
+				require.ErrorContains(err, test.expectedErr)
+			} else {
+				require.NoError(err)
+			}
+
+			// other resources are pruned despite the failure
+			_, ok := server.Image("tcontainer/pruned")
+			assert.False(ok)
+			_, ok = server.Image("busybox")
+			assert.True(ok, "side image unexpectedly removed")
+			for _, network := range server.Networks() {
+				assert.Empty(network.Labels)
+			}
+			if test.failure.Path != `^/volumes$` {
+				assert.Equal([]string{"side"}, volumeNames(server.Volumes()))
+			}
+		})
+	}
+}
+
+func Test_Server_Network(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+
+	network, err := pool.CreateNetwork(ctx, tcontainer.WithNetworkName(t.Name()))
+	require.NoError(err)
+
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("server"), tcontainer.WithNetwork(network, "api"))
+	require.NoError(err)
+	client, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("client"), tcontainer.WithNetwork(network))
+	require.NoError(err)
+
+	endpoint := container.Container.NetworkSettings.Networks[t.Name()]
+	require.NotEmpty(endpoint.IPAddress)
+	require.Contains(endpoint.Aliases, "api")
+
+	heal, err := pool.Partition(ctx, container, client)
+	require.NoError(err)
+	fakeNetwork, ok := server.Network(t.Name())
+	require.True(ok)
+	require.NotContains(fakeNetwork.Containers, client.Container.ID)
+
+	require.NoError(heal(ctx))
+	fakeNetwork, ok = server.Network(t.Name())
+	require.True(ok)
+	require.Contains(fakeNetwork.Containers, client.Container.ID)
+
+	found, err := pool.NetworkByName(ctx, t.Name())
+	require.NoError(err)
+	require.Equal(network.Network.ID, found.Network.ID)
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = pool.NetworkByName(canceledCtx, t.Name())
+	require.ErrorIs(err, context.Canceled)
+}
+
+func Test_Server_Run_MultipleNetworks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	_, pool := newPool(t)
+
+	first, err := pool.CreateNetwork(ctx, tcontainer.WithNetworkName("first"))
+	require.NoError(err)
+	second, err := pool.CreateNetwork(ctx, tcontainer.WithNetworkName("second"))
+	require.NoError(err)
+
+	// the fake (API 1.43) rejects more than one network at creation, so the second one is connected after it
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"),
+		tcontainer.WithNetwork(first, "api"), tcontainer.WithNetwork(second, "backend"))
+	require.NoError(err)
+
+	require.Contains(container.Container.NetworkSettings.Networks["first"].Aliases, "api")
+	require.Contains(container.Container.NetworkSettings.Networks["second"].Aliases, "backend")
+	require.Contains(second.Network.Containers, container.Container.ID)
+}
+
+func Test_Server_Build(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+
+	image, err := pool.BuildAndGet(ctx,
+		tcontainer.WithContextDir("../internal/testing"),
+		tcontainer.WithDockerfile("Dockerfile.test"),
+		tcontainer.WithImageName("tcontainer/built"),
+		tcontainer.WithLabels(map[string]string{"custom": "label"}),
+	)
+	require.NoError(err)
+	require.Equal([]string{"tcontainer/built:latest"}, image.RepoTags)
+	require.Equal("label", image.Config.Labels["custom"])
+
+	_, err = pool.BuildAndGet(ctx, tcontainer.WithContextDir("../internal/testing"), tcontainer.WithDockerfile("missing"))
+	require.ErrorContains(err, "Cannot locate specified Dockerfile")
+
+	require.Len(server.Images(), 1)
+}
+
+func volumeNames(volumes []docker.Volume) (names []string) {
+	for _, volume := range volumes {
+		names = append(names, volume.Name)
+	}
+
+	return names
+}
+
+func Test_Server_Run_Canceled(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name    string
+		failure dockerfake.Failure
+		opts    []tcontainer.RunOption
+		network bool `exhaustruct:"optional"`
+	}
+	// requests without context are not canceled by the client, so they are delayed less than a minute
+	testCases := []testCase{
+		{
+			name:    "hung image inspect",
+			failure: dockerfake.Failure{Method: http.MethodGet, Path: `^/images/.+/json$`, Delay: time.Second * 2},
+		},
+		{
+			name:    "hung pull",
+			failure: dockerfake.Failure{Method: http.MethodPost, Path: `^/images/create$`, Delay: time.Minute},
+		},
+		{
+			name:    "hung create",
+			failure: dockerfake.Failure{Method: http.MethodPost, Path: `^/containers/create$`, Delay: time.Minute},
+		},
+		{
+			name:    "hung start",
+			failure: dockerfake.Failure{Method: http.MethodPost, Path: `^/containers/[^/]+/start$`, Delay: time.Minute},
+		},
+		{
+			name:    "hung network refresh",
+			failure: dockerfake.Failure{Method: http.MethodGet, Path: `^/networks/[^/]+$`, Delay: time.Second * 2},
+			network: true,
+		},
+		{
+			name: "never ready",
+			opts: []tcontainer.RunOption{tcontainer.WithRetry(func(context.Context, *tcontainer.Container) error {
+				return errors.New("not ready")
+			}, backoff.NewConstantBackOff(time.Millisecond*10))},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			server, pool := newPool(t)
+			opts := append([]tcontainer.RunOption{tcontainer.WithContainerName(t.Name())}, test.opts...)
+			if test.network {
+				network, err := pool.CreateNetwork(context.Background())
+				require.NoError(err)
+				opts = append(opts, tcontainer.WithNetwork(network))
+			}
+			if test.failure.Path != "" {
+				server.Fail(test.failure)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+			defer cancel()
+
+			start := time.Now()
+			_, err := pool.Run(ctx, "busybox", opts...)
+			require.ErrorIs(err, context.DeadlineExceeded)
+			require.Less(time.Since(start), time.Second)
+
+			// partially created container is removed
+			require.Eventually(func() bool { return len(server.Containers()) == 0 }, time.Second, time.Millisecond*10)
+		})
+	}
+}
+
+func Test_Server_Run_StartupTimeout(t *testing.T) {
+	t.Parallel()
+
+	neverReady := tcontainer.WithRetry(func(context.Context, *tcontainer.Container) error {
+		return errors.New("not ready")
+	}, backoff.NewConstantBackOff(time.Millisecond*10))
+
+	type testCase struct {
+		name          string
+		failurePath   string
+		opts          []tcontainer.RunOption
+		expectedPhase tcontainer.StartupPhase
+		expectedTotal bool
+	}
+	testCases := []testCase{
+		{
+			name:          "pull phase timeout",
+			failurePath:   `^/images/create$`,
+			opts:          []tcontainer.RunOption{tcontainer.WithPhaseTimeout(tcontainer.StartupPhasePull, time.Millisecond*100)},
+			expectedPhase: tcontainer.StartupPhasePull,
+		},
+		{
+			name:          "create phase timeout",
+			failurePath:   `^/containers/create$`,
+			opts:          []tcontainer.RunOption{tcontainer.WithPhaseTimeout(tcontainer.StartupPhaseCreate, time.Millisecond*100)},
+			expectedPhase: tcontainer.StartupPhaseCreate,
+		},
+		{
+			name:          "startup timeout in start phase",
+			failurePath:   `^/containers/[^/]+/start$`,
+			opts:          []tcontainer.RunOption{tcontainer.WithStartupTimeout(time.Millisecond * 200)},
+			expectedPhase: tcontainer.StartupPhaseStart,
+			expectedTotal: true,
+		},
+		{
+			name: "ready phase timeout",
+			opts: []tcontainer.RunOption{
+				neverReady,
+				tcontainer.WithStartupTimeout(time.Minute),
+				tcontainer.WithPhaseTimeout(tcontainer.StartupPhaseReady, time.Millisecond*100),
+			},
+			expectedPhase: tcontainer.StartupPhaseReady,
+		},
+		{
+			name:          "startup timeout in ready phase",
+			opts:          []tcontainer.RunOption{neverReady, tcontainer.WithStartupTimeout(time.Millisecond * 200)},
+			expectedPhase: tcontainer.StartupPhaseReady,
+			expectedTotal: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			server, pool := newPool(t)
+			if test.failurePath != "" {
+				server.Fail(dockerfake.Failure{Method: http.MethodPost, Path: test.failurePath, Delay: time.Minute})
+			}
+
+			opts := append([]tcontainer.RunOption{tcontainer.WithContainerName(t.Name())}, test.opts...)
+			_, err := pool.Run(context.Background(), "busybox", opts...)
+			require.ErrorIs(err, tcontainer.ErrStartupTimeout)
+			require.ErrorIs(err, context.DeadlineExceeded)
+
+			var timeoutErr *tcontainer.StartupTimeoutError
+			require.ErrorAs(err, &timeoutErr)
+			require.Equal(test.expectedPhase, timeoutErr.Phase, err.Error())
+			require.Equal(test.expectedTotal, timeoutErr.Total, err.Error())
+
+			require.Eventually(func() bool { return len(server.Containers()) == 0 }, time.Second, time.Millisecond*10)
+		})
+	}
+}
+
+func Test_Server_Run_RunError(t *testing.T) {
+	t.Parallel()
+
+	const name = "app"
+
+	type testCase struct {
+		name          string
+		prepare       func(t *testing.T, server *dockerfake.Server, pool tcontainer.Pool) []tcontainer.RunOption
+		expectedErr   error
+		expectedPhase tcontainer.StartupPhase
+		expectedLogs  string
+	}
+	testCases := []testCase{
+		{
+			name: "image not found",
+			prepare: func(_ *testing.T, server *dockerfake.Server, _ tcontainer.Pool) []tcontainer.RunOption {
+				server.Fail(dockerfake.Failure{
+					Method:  http.MethodPost,
+					Path:    `^/images/create$`,
+					Status:  http.StatusNotFound,
+					Message: "pull access denied for busybox, repository does not exist",
+				})
+
+				return nil
+			},
+			expectedErr:   tcontainer.ErrImageNotFound,
+			expectedPhase: tcontainer.StartupPhasePull,
+		},
+		{
+			name: "port in use",
+			prepare: func(t *testing.T, _ *dockerfake.Server, pool tcontainer.Pool) []tcontainer.RunOption {
+				_, err := pool.Run(context.Background(), "busybox",
+					tcontainer.WithContainerName("first"), tcontainer.WithPortBinding("80", "18080"))
+				require.NoError(t, err)
+
+				return []tcontainer.RunOption{tcontainer.WithPortBinding("80", "18080")}
+			},
+			expectedErr:   tcontainer.ErrPortInUse,
+			expectedPhase: tcontainer.StartupPhaseStart,
+		},
+		{
+			name: "readiness failed",
+			prepare: func(_ *testing.T, server *dockerfake.Server, _ tcontainer.Pool) []tcontainer.RunOption {
+				return []tcontainer.RunOption{
+					tcontainer.WithRetry(func(context.Context, *tcontainer.Container) error {
+						_ = server.AppendLogs(name, "", "fatal: config not found\n")
+						return errors.New("not ready")
+					}, backoff.NewConstantBackOff(time.Millisecond*10)),
+					tcontainer.WithPhaseTimeout(tcontainer.StartupPhaseReady, time.Millisecond*100),
+				}
+			},
+			expectedErr:   tcontainer.ErrReadinessFailed,
+			expectedPhase: tcontainer.StartupPhaseReady,
+			expectedLogs:  "fatal: config not found",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			server, pool := newPool(t)
+			opts := append([]tcontainer.RunOption{tcontainer.WithContainerName(name)}, test.prepare(t, server, pool)...)
+
+			_, err := pool.Run(context.Background(), "busybox", opts...)
+			require.ErrorIs(err, test.expectedErr)
+
+			var runErr *tcontainer.RunError
+			require.ErrorAs(err, &runErr)
+			require.Equal(test.expectedPhase, runErr.Phase, err.Error())
+			require.Equal(name, runErr.Name)
+			require.Equal("busybox:latest", runErr.Image)
+			require.Contains(runErr.Logs, test.expectedLogs)
+			if test.expectedLogs != "" {
+				require.NotEmpty(runErr.ContainerID)
+			}
+
+			_, ok := server.Container(name)
+			require.False(ok)
+		})
+	}
+}
+
+func Test_Server_Logger(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+	output := &bytes.Buffer{}
+	pool = pool.WithLogger(slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	attempt := 0
+	container, err := pool.Run(ctx, "busybox",
+		tcontainer.WithContainerName("app"),
+		tcontainer.WithExpiry(time.Hour),
+		tcontainer.WithRetry(func(context.Context, *tcontainer.Container) error {
+			attempt++
+			if attempt == 1 {
+				return errors.New("not ready")
+			}
+			return nil
+		}, backoff.NewConstantBackOff(time.Millisecond)),
+	)
+	require.NoError(err)
+
+	require.NoError(server.UpdateContainer("app", func(container *docker.Container) {
+		container.State.Running = false
+		container.State.Status = "exited"
+	}))
+	_, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"), tcontainer.WithReuse(false))
+	require.NoError(err)
+
+	err = pool.Build(ctx, tcontainer.WithContextDir("../internal/testing"), tcontainer.WithDockerfile("Dockerfile.test"))
+	require.NoError(err)
+
+	require.NoError(pool.Prune(ctx))
+
+	logs := output.String()
+	for _, expected := range []string{
+		`msg="pulling image" image=busybox:latest`,
+		`msg="container created" container_name=app container_id=` + container.Container.ID,
+		`msg="container started" container_name=app`,
+		`msg="container expiry scheduled" container_name=app`,
+		`msg="readiness check attempt failed" container_name=app`,
+		`attempt=1 error="not ready"`,
+		`msg="container is ready" container_name=app`,
+		`msg="container already exists, trying to reuse it" container_name=app`,
+		`msg="repairing container for reuse" container_name=app`,
+		`action=start`,
+		`msg="container reused" container_name=app`,
+		`repaired=true`,
+		`msg="building image"`,
+		`msg="image built"`,
+		`msg="container pruned" container_name=app`,
+	} {
+		require.Contains(logs, expected)
+	}
+}
+
+func Test_Server_Run_StartupStats(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server, pool := newPool(t)
+	server.Fail(dockerfake.Failure{Method: http.MethodPost, Path: `^/images/create$`, Delay: time.Millisecond * 50})
+
+	attempt := 0
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"),
+		tcontainer.WithRetry(func(context.Context, *tcontainer.Container) error {
+			attempt++
+			if attempt == 1 {
+				return errors.New("not ready")
+			}
+			return nil
+		}, backoff.NewConstantBackOff(time.Millisecond*10)),
+	)
+	require.NoError(err)
+
+	stats := container.StartupStats()
+	require.GreaterOrEqual(stats.Pull, time.Millisecond*50)
+	require.Positive(stats.Create)
+	require.Positive(stats.Start)
+	require.GreaterOrEqual(stats.Ready, time.Millisecond*10)
+	require.Equal(1, stats.RetryAttempts)
+	require.GreaterOrEqual(stats.Total, stats.Pull+stats.Create+stats.Start+stats.Ready)
+	require.Equal(pool.Stats(), container.Pool().Stats())
+
+	// stats are shared by derived pools
+	reused, err := pool.WithDefaults().Run(ctx, "busybox", tcontainer.WithContainerName("app"), tcontainer.WithReuse(false))
+	require.NoError(err)
+	require.Zero(reused.StartupStats().Start)
+	require.Zero(reused.StartupStats().RetryAttempts)
+
+	_, err = pool.Run(ctx, "alpine")
+	require.NoError(err)
+
+	poolStats := pool.Stats()
+	require.Len(poolStats.Images, 2)
+
+	busybox := poolStats.Images[0]
+	require.Equal("busybox:latest", busybox.Image)
+	require.Equal(2, busybox.Runs)
+	require.Equal(map[tcontainer.ContainerOrigin]int{
+		tcontainer.ContainerOriginCreated: 1,
+		tcontainer.ContainerOriginReused:  1,
+	}, busybox.Origins)
+	require.Equal(1, busybox.Sum.RetryAttempts)
+	require.Equal(stats.Total+reused.StartupStats().Total, busybox.Sum.Total)
+	require.Equal(max(stats.Total, reused.StartupStats().Total), busybox.MaxTotal)
+	require.Equal("alpine:latest", poolStats.Images[1].Image)
+
+	table := poolStats.String()
+	require.Contains(table, "IMAGE")
+	require.Contains(table, "busybox:latest")
+	require.Contains(table, "alpine:latest")
+}
@@ -0,0 +1,39 @@
+package dockerfake_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ory/dockertest/v3/docker"
+
+	"github.com/kiteggrad/tcontainer"
+	"github.com/kiteggrad/tcontainer/dockerfake"
+)
+
+func ExampleServer() {
+	server := dockerfake.NewServer()
+	defer server.Close()
+
+	// script the result of commands executed in containers
+	server.SetExecHandler(func(docker.Container, []string) dockerfake.ExecResult {
+		return dockerfake.ExecResult{ExitCode: 0, Stdout: "migrated", Stderr: ""}
+	})
+
+	pool := tcontainer.NewPoolWithClient(server.Client())
+
+	container, err := pool.Run(context.Background(), "postgres", tcontainer.WithContainerName("db"))
+	if err != nil {
+		panic(err)
+	}
+	defer container.Close()
+
+	result, err := container.Exec(context.Background(), "migrate", "up")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(container.Container.Name, result.Stdout)
+
+	// Output:
+	// /db migrated
+}
@@ -0,0 +1,208 @@
+package dockerfake
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/ory/dockertest/v3/docker/pkg/stdcopy"
+)
+
+const (
+	multiplexedStreamContentType = "application/vnd.docker.multiplexed-stream"
+	rawStreamContentType         = "application/vnd.docker.raw-stream"
+)
+
+func (s *Server) handleContainerLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	withStdout, withStderr := isTrue(query.Get("stdout")), isTrue(query.Get("stderr"))
+
+	s.mu.Lock()
+	container := s.findContainer(r.PathValue("id"))
+	if container == nil {
+		s.mu.Unlock()
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return
+	}
+	logs := slices.Clone(container.logs)
+	tty := container.Config.Tty
+	s.mu.Unlock()
+
+	contentType := multiplexedStreamContentType
+	if tty {
+		contentType = rawStreamContentType
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	stdout, stderr := outputStreams(w, tty)
+	for _, entry := range logs {
+		switch {
+		case entry.stderr && withStderr:
+			_, _ = stderr.Write(entry.data)
+		case !entry.stderr && withStdout:
+			_, _ = stdout.Write(entry.data)
+		}
+	}
+}
+
+func (s *Server) handleExecCreate(w http.ResponseWriter, r *http.Request) {
+	var body docker.CreateExecOptions
+	err := decodeBody(r, &body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(body.Cmd) == 0 {
+		writeError(w, http.StatusBadRequest, "No exec command specified")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container := s.findContainer(r.PathValue("id"))
+	switch {
+	case container == nil:
+		writeNoSuchContainer(w, r.PathValue("id"))
+		return
+	case !container.State.Running:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is not running", container.ID))
+		return
+	case container.State.Paused:
+		writeError(w, http.StatusConflict, fmt.Sprintf(
+			"Container %s is paused, unpause the container before exec", container.ID,
+		))
+		return
+	}
+
+	exec := &execInstance{
+		ExecInspect: docker.ExecInspect{ //nolint:exhaustruct
+			ID:         newID(),
+			OpenStdout: body.AttachStdout,
+			OpenStderr: body.AttachStderr,
+			OpenStdin:  body.AttachStdin,
+			ProcessConfig: docker.ExecProcessConfig{ //nolint:exhaustruct
+				Privileged: body.Privileged,
+				User:       body.User,
+				Tty:        body.Tty,
+				EntryPoint: body.Cmd[0],
+				Arguments:  body.Cmd[1:],
+			},
+			ContainerID: container.ID,
+		},
+		cmd: body.Cmd,
+	}
+	s.execs[exec.ID] = exec
+	container.ExecIDs = append(container.ExecIDs, exec.ID)
+
+	writeJSON(w, http.StatusCreated, map[string]string{"Id": exec.ID})
+}
+
+func (s *Server) handleExecStart(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Detach bool
+		Tty    bool
+	}
+	err := decodeBody(r, &body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	exec := s.execs[r.PathValue("id")]
+	if exec == nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "No such exec instance: "+r.PathValue("id"))
+		return
+	}
+	container := s.containers[exec.ContainerID]
+	if container == nil || !container.State.Running {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is not running", exec.ContainerID))
+		return
+	}
+	handler, snapshot, cmd := s.execHandler, clone(container.Container), exec.cmd
+	exec.Running = true
+	s.mu.Unlock()
+
+	// handler is called without lock, so it can use the server (e.g. UpdateContainer)
+	result := handler(snapshot, slices.Clone(cmd))
+
+	s.mu.Lock()
+	exec.Running = false
+	exec.ExitCode = result.ExitCode
+	tty := exec.ProcessConfig.Tty
+	s.mu.Unlock()
+
+	if body.Detach {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = writeUpgradedStream(w, tty, result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (s *Server) handleExecInspect(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exec := s.execs[r.PathValue("id")]
+	if exec == nil {
+		writeError(w, http.StatusNotFound, "No such exec instance: "+r.PathValue("id"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, exec.ExecInspect)
+}
+
+// writeUpgradedStream - hijacks the connection as docker does for attached exec and writes output to it.
+func writeUpgradedStream(w http.ResponseWriter, tty bool, result ExecResult) (err error) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return fmt.Errorf("%T doesn't support hijacking", w)
+	}
+
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return fmt.Errorf("failed to Hijack: %w", err)
+	}
+	defer conn.Close()
+
+	contentType := multiplexedStreamContentType
+	if tty {
+		contentType = rawStreamContentType
+	}
+	_, err = fmt.Fprintf(buffer,
+		"HTTP/1.1 101 UPGRADED\r\nContent-Type: %s\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n", contentType,
+	)
+	if err != nil {
+		return nil //nolint:nilerr // the client has gone, nothing to respond
+	}
+
+	stdout, stderr := outputStreams(buffer, tty)
+	if result.Stdout != "" {
+		_, _ = io.WriteString(stdout, result.Stdout)
+	}
+	if result.Stderr != "" {
+		_, _ = io.WriteString(stderr, result.Stderr)
+	}
+	_ = buffer.Flush()
+
+	return nil
+}
+
+// outputStreams - returns writers of stdout and stderr multiplexed into w (tty output isn't multiplexed).
+func outputStreams(w io.Writer, tty bool) (stdout, stderr io.Writer) {
+	if tty {
+		return w, w
+	}
+
+	return stdcopy.NewStdWriter(w, stdcopy.Stdout), stdcopy.NewStdWriter(w, stdcopy.Stderr)
+}
@@ -0,0 +1,80 @@
+package dockerfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type filters map[string][]string
+
+// parseFilters - parses `filters` query parameter.
+// Both formats used by clients are supported: {"label":["a=b"]} and {"label":{"a=b":true}}.
+func parseFilters(raw string, allowedKeys ...string) (parsed filters, err error) {
+	parsed = filters{}
+	if raw == "" {
+		return parsed, nil
+	}
+
+	err = json.Unmarshal([]byte(raw), &parsed)
+	if err != nil {
+		legacy := map[string]map[string]bool{}
+		legacyErr := json.Unmarshal([]byte(raw), &legacy)
+		if legacyErr != nil {
+			return nil, fmt.Errorf("invalid filters `%s`: %w", raw, err)
+		}
+
+		parsed = make(filters, len(legacy))
+		for key, values := range legacy {
+			for value, enabled := range values {
+				if enabled {
+					parsed[key] = append(parsed[key], value)
+				}
+			}
+		}
+	}
+
+	for key := range parsed {
+		if !slices.Contains(allowedKeys, key) {
+			return nil, fmt.Errorf("invalid filter '%s'", key)
+		}
+	}
+
+	return parsed, nil
+}
+
+// matchAny - true if there is no filter by key or value matches any of filter values by match func.
+func (f filters) matchAny(key string, match func(filterValue string) bool) bool {
+	values, ok := f[key]
+	if !ok || len(values) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(values, match)
+}
+
+// matchLabels - true if labels match all "label" filters ("key" or "key=value").
+func (f filters) matchLabels(labels map[string]string) bool {
+	for _, filter := range f["label"] {
+		key, value, withValue := strings.Cut(filter, "=")
+
+		labelValue, ok := labels[key]
+		if !ok || (withValue && labelValue != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchRegexp - matches value by filter as docker does for names (invalid regexp never matches).
+func matchRegexp(filter, value string) bool {
+	re, err := regexp.Compile(filter)
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(value)
+}
@@ -0,0 +1,257 @@
+package dockerfake
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const defaultDockerfile = "Dockerfile"
+
+func (s *Server) handleImageList(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseFilters(r.URL.Query().Get("filters"), "label", "reference", "dangling")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	images := []docker.APIImages{}
+	for _, image := range s.images {
+		matched := filters.matchLabels(image.Config.Labels) &&
+			filters.matchAny("reference", func(filter string) bool {
+				return slices.ContainsFunc(image.RepoTags, func(tag string) bool {
+					matched, _ := path.Match(normalizeRef(filter), tag)
+					return matched
+				})
+			}) &&
+			filters.matchAny("dangling", func(filter string) bool { return isTrue(filter) == (len(image.RepoTags) == 0) })
+		if !matched {
+			continue
+		}
+
+		images = append(images, docker.APIImages{ //nolint:exhaustruct
+			ID:       image.ID,
+			RepoTags: image.RepoTags,
+			Created:  image.Created.Unix(),
+			Size:     image.Size,
+			Labels:   image.Config.Labels,
+		})
+	}
+	slices.SortFunc(images, func(a, b docker.APIImages) int { return int(b.Created - a.Created) })
+
+	writeJSON(w, http.StatusOK, images)
+}
+
+func (s *Server) handleImageInspect(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("name"), "/json")
+	if !ok {
+		writeNotImplemented(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	image := s.findImage(name)
+	if image == nil {
+		writeError(w, http.StatusNotFound, "No such image: "+name)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, image)
+}
+
+// handleImagePull - "pulls" image from the fake registry: every image exists there.
+// Use (*Server).Fail to simulate missing image or registry errors.
+func (s *Server) handleImagePull(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	ref := query.Get("fromImage")
+	if ref == "" {
+		writeError(w, http.StatusBadRequest, "fromImage is required")
+		return
+	}
+	if tag := query.Get("tag"); tag != "" && !strings.HasPrefix(tag, "sha256:") {
+		ref = ref + ":" + tag
+	} else if tag != "" {
+		ref = ref + "@" + tag
+	}
+
+	s.mu.Lock()
+	if s.findImage(ref) == nil {
+		s.addImage([]string{ref}, nil)
+	}
+	s.mu.Unlock()
+
+	writeJSONStream(w,
+		map[string]string{"status": "Pulling from " + ref},
+		map[string]string{"status": "Status: Downloaded newer image for " + normalizeRef(ref)},
+	)
+}
+
+// handleImageBuild - "builds" image: checks that build context contains the Dockerfile
+// and creates image with the tags and labels, instructions of the Dockerfile aren't executed.
+func (s *Server) handleImageBuild(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	labels := map[string]string{}
+	if raw := query.Get("labels"); raw != "" {
+		err := json.Unmarshal([]byte(raw), &labels)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid labels: %s", err))
+			return
+		}
+	}
+
+	if query.Get("remote") == "" {
+		dockerfile := query.Get("dockerfile")
+		if dockerfile == "" {
+			dockerfile = defaultDockerfile
+		}
+
+		err := findInTar(r.Body, dockerfile)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "Cannot locate specified Dockerfile: "+dockerfile)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	image := s.addImage(query["t"], labels)
+	s.mu.Unlock()
+
+	messages := []any{
+		map[string]any{"aux": map[string]string{"ID": image.ID}},
+		map[string]string{"stream": fmt.Sprintf("Successfully built %s\n", shortID(image.ID))},
+	}
+	for _, tag := range image.RepoTags {
+		messages = append(messages, map[string]string{"stream": fmt.Sprintf("Successfully tagged %s\n", tag)})
+	}
+	writeJSONStream(w, messages...)
+}
+
+func (s *Server) handleImageTag(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("name"), "/tag")
+	if !ok {
+		writeNotImplemented(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	ref := query.Get("repo")
+	if ref == "" {
+		writeError(w, http.StatusBadRequest, "repository name (repo) is required")
+		return
+	}
+	if tag := query.Get("tag"); tag != "" {
+		ref = ref + ":" + tag
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	image := s.findImage(name)
+	if image == nil {
+		writeError(w, http.StatusNotFound, "No such image: "+name)
+		return
+	}
+
+	if !slices.Contains(image.RepoTags, normalizeRef(ref)) {
+		s.tagImage(image, ref)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleImageRemove(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	force := isTrue(r.URL.Query().Get("force"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	image := s.findImage(name)
+	if image == nil {
+		writeError(w, http.StatusNotFound, "No such image: "+name)
+		return
+	}
+
+	// removal by one of the tags only untags the image
+	ref := normalizeRef(name)
+	if len(image.RepoTags) > 1 && slices.Contains(image.RepoTags, ref) {
+		image.RepoTags = slices.DeleteFunc(image.RepoTags, func(tag string) bool { return tag == ref })
+		writeJSON(w, http.StatusOK, []map[string]string{{"Untagged": ref}})
+		return
+	}
+
+	for _, container := range s.sortedContainers() {
+		if container.Image != image.ID || (force && !container.State.Running) {
+			continue
+		}
+
+		state := "stopped"
+		if container.State.Running {
+			state = "running"
+		}
+		writeError(w, http.StatusConflict, fmt.Sprintf(
+			"conflict: unable to delete %s (must be forced) - image is being used by %s container %s",
+			shortID(image.ID), state, shortID(container.ID),
+		))
+		return
+	}
+
+	response := make([]map[string]string, 0, len(image.RepoTags)+1)
+	for _, tag := range image.RepoTags {
+		response = append(response, map[string]string{"Untagged": tag})
+	}
+	response = append(response, map[string]string{"Deleted": image.ID})
+	delete(s.images, image.ID)
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// findInTar - checks that tar archive contains the file.
+func findInTar(archive io.Reader, name string) (err error) {
+	name = path.Clean(name)
+	reader := tar.NewReader(archive)
+
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("file `%s` not found", name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar: %w", err)
+		}
+
+		if path.Clean(header.Name) == name {
+			return nil
+		}
+	}
+}
+
+// writeJSONStream - writes progress messages as docker does for pull and build.
+func writeJSONStream(w http.ResponseWriter, messages ...any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, message := range messages {
+		_ = encoder.Encode(message)
+	}
+}
+
+func writeNotImplemented(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotImplemented, fmt.Sprintf("dockerfake: %s %s is not implemented", r.Method, r.URL.Path))
+}
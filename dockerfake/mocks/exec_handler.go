@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package dockerfake_mocks
+
+import (
+	dockerfake "github.com/kiteggrad/tcontainer/dockerfake"
+	docker "github.com/ory/dockertest/v3/docker"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExecHandler is an autogenerated mock type for the ExecHandler type
+type ExecHandler struct {
+	mock.Mock
+}
+
+type ExecHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ExecHandler) EXPECT() *ExecHandler_Expecter {
+	return &ExecHandler_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: container, cmd
+func (_m *ExecHandler) Execute(container docker.Container, cmd []string) dockerfake.ExecResult {
+	ret := _m.Called(container, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 dockerfake.ExecResult
+	if rf, ok := ret.Get(0).(func(docker.Container, []string) dockerfake.ExecResult); ok {
+		r0 = rf(container, cmd)
+	} else {
+		r0 = ret.Get(0).(dockerfake.ExecResult)
+	}
+
+	return r0
+}
+
+// ExecHandler_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type ExecHandler_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - container docker.Container
+//   - cmd []string
+func (_e *ExecHandler_Expecter) Execute(container interface{}, cmd interface{}) *ExecHandler_Execute_Call {
+	return &ExecHandler_Execute_Call{Call: _e.mock.On("Execute", container, cmd)}
+}
+
+func (_c *ExecHandler_Execute_Call) Run(run func(container docker.Container, cmd []string)) *ExecHandler_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(docker.Container), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *ExecHandler_Execute_Call) Return(_a0 dockerfake.ExecResult) *ExecHandler_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ExecHandler_Execute_Call) RunAndReturn(run func(docker.Container, []string) dockerfake.ExecResult) *ExecHandler_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExecHandler creates a new instance of ExecHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExecHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExecHandler {
+	mock := &ExecHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,270 @@
+package dockerfake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+func (s *Server) handleNetworkList(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseFilters(r.URL.Query().Get("filters"), "name", "id", "label", "driver")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	networks := []docker.Network{}
+	for _, network := range s.sortedNetworks() {
+		matched := filters.matchAny("name", func(filter string) bool { return strings.Contains(network.Name, filter) }) &&
+			filters.matchAny("id", func(filter string) bool { return strings.HasPrefix(network.ID, filter) }) &&
+			filters.matchAny("driver", func(filter string) bool { return network.Driver == filter }) &&
+			filters.matchLabels(network.Labels)
+		if matched {
+			networks = append(networks, network.Network)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, networks)
+}
+
+func (s *Server) handleNetworkInspect(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	network := s.findNetwork(r.PathValue("id"))
+	if network == nil {
+		writeNoSuchNetwork(w, r.PathValue("id"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, network.Network)
+}
+
+func (s *Server) handleNetworkCreate(w http.ResponseWriter, r *http.Request) {
+	var body docker.CreateNetworkOptions
+	err := decodeBody(r, &body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, "network name is required")
+		return
+	}
+
+	driver := body.Driver
+	if driver == "" {
+		driver = bridgeNetwork
+	}
+	if driver != bridgeNetwork {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("dockerfake: network driver %s is not supported", driver))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, network := range s.networks {
+		if network.Name == body.Name {
+			writeError(w, http.StatusConflict, fmt.Sprintf("network with name %s already exists", body.Name))
+			return
+		}
+	}
+
+	network := s.addNetwork(body.Name, driver, body.Labels)
+	network.Internal = body.Internal
+	network.EnableIPv6 = body.EnableIPv6
+
+	writeJSON(w, http.StatusCreated, map[string]string{"Id": network.ID, "Warning": ""})
+}
+
+func (s *Server) handleNetworkRemove(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	network := s.findNetwork(r.PathValue("id"))
+	switch {
+	case network == nil:
+		writeNoSuchNetwork(w, r.PathValue("id"))
+		return
+	case network.isPredefined():
+		writeError(w, http.StatusForbidden, fmt.Sprintf("%s is a pre-defined network and cannot be removed", network.Name))
+		return
+	case len(network.Containers) != 0:
+		writeError(w, http.StatusForbidden, fmt.Sprintf(
+			"error while removing network: network %s id %s has active endpoints", network.Name, network.ID,
+		))
+		return
+	}
+
+	// stopped containers lose the endpoint
+	for _, container := range s.containers {
+		delete(container.NetworkSettings.Networks, network.Name)
+	}
+	delete(s.networks, network.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleNetworkConnect(w http.ResponseWriter, r *http.Request) {
+	var body docker.NetworkConnectionOptions
+	err := decodeBody(r, &body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	network, container, ok := s.findEndpoint(w, r.PathValue("id"), body.Container)
+	if !ok {
+		return
+	}
+
+	if _, connected := container.NetworkSettings.Networks[network.Name]; connected {
+		writeError(w, http.StatusForbidden, fmt.Sprintf(
+			"endpoint with name %s already exists in network %s", strings.TrimPrefix(container.Name, "/"), network.Name,
+		))
+		return
+	}
+	if network.Name == hostNetwork || network.Name == noneNetwork {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf(
+			"container cannot be disconnected from host network or connected to %s network", network.Name,
+		))
+		return
+	}
+
+	var aliases []string
+	if body.EndpointConfig != nil {
+		aliases = body.EndpointConfig.Aliases
+	}
+	if container.NetworkSettings.Networks == nil {
+		container.NetworkSettings.Networks = map[string]docker.ContainerNetwork{}
+	}
+	container.NetworkSettings.Networks[network.Name] = newEndpoint(container.ID, network, aliases)
+
+	if container.State.Running {
+		s.attach(container, network, network.Name)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleNetworkDisconnect(w http.ResponseWriter, r *http.Request) {
+	var body docker.NetworkConnectionOptions
+	err := decodeBody(r, &body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	network, container, ok := s.findEndpoint(w, r.PathValue("id"), body.Container)
+	if !ok {
+		return
+	}
+
+	if _, connected := container.NetworkSettings.Networks[network.Name]; !connected {
+		writeError(w, http.StatusForbidden, fmt.Sprintf(
+			"container %s is not connected to network %s", container.ID, network.Name,
+		))
+		return
+	}
+
+	delete(container.NetworkSettings.Networks, network.Name)
+	delete(network.Containers, container.ID)
+	if network.Name == bridgeNetwork {
+		container.NetworkSettings.IPAddress = ""
+		container.NetworkSettings.Gateway = ""
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleVolumeList(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseFilters(r.URL.Query().Get("filters"), "name", "label", "dangling")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	volumes := []docker.Volume{}
+	for _, volume := range s.volumes {
+		matched := filters.matchAny("name", func(filter string) bool { return strings.Contains(volume.Name, filter) }) &&
+			filters.matchAny("dangling", func(filter string) bool { return isTrue(filter) == !s.isVolumeUsed(volume.Name) }) &&
+			filters.matchLabels(volume.Labels)
+		if matched {
+			volumes = append(volumes, *volume)
+		}
+	}
+	slices.SortFunc(volumes, func(a, b docker.Volume) int { return strings.Compare(a.Name, b.Name) })
+
+	writeJSON(w, http.StatusOK, map[string]any{"Volumes": volumes, "Warnings": nil})
+}
+
+func (s *Server) handleVolumeRemove(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.volumes[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("get %s: no such volume", name))
+		return
+	}
+	if s.isVolumeUsed(name) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("remove %s: volume is in use", name))
+		return
+	}
+
+	delete(s.volumes, name)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findEndpoint - finds network and container of connect/disconnect request, writes error response if not found.
+// Requires s.mu.
+func (s *Server) findEndpoint(
+	w http.ResponseWriter, networkID, containerID string,
+) (network *network, container *container, ok bool) {
+	network = s.findNetwork(networkID)
+	if network == nil {
+		writeNoSuchNetwork(w, networkID)
+		return nil, nil, false
+	}
+
+	container = s.findContainer(containerID)
+	if container == nil {
+		writeNoSuchContainer(w, containerID)
+		return nil, nil, false
+	}
+
+	return network, container, true
+}
+
+// isVolumeUsed - checks that volume is mounted to any container. Requires s.mu.
+func (s *Server) isVolumeUsed(name string) bool {
+	for _, container := range s.containers {
+		if slices.Contains(container.volumes, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeNoSuchNetwork(w http.ResponseWriter, id string) {
+	writeError(w, http.StatusNotFound, fmt.Sprintf("network %s not found", id))
+}
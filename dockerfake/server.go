@@ -0,0 +1,326 @@
+// Package dockerfake - in-process fake of the Docker Engine API (the subset used by tcontainer)
+// for hermetic unit tests without docker daemon.
+//
+// The fake keeps containers, images, networks and volumes in memory, doesn't run any processes
+// and allows to script the state (see UpdateContainer, AddImage, SetExecHandler) and inject failures (see Fail).
+// Use tcontainer.NewPoolWithClient(server.Client()) to run tcontainer against it.
+package dockerfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	// APIVersion - Engine API version reported by the server.
+	APIVersion = "1.43"
+
+	minAPIVersion = "1.12"
+)
+
+var apiVersionPrefixRegexp = regexp.MustCompile(`^/v[0-9]+\.[0-9]+/`)
+
+type (
+	// Server - fake Docker Engine API server. Use NewServer to create it.
+	Server struct {
+		httpServer *httptest.Server
+		mux        *http.ServeMux
+		closed     chan struct{}
+		closeOnce  sync.Once
+
+		mu          sync.Mutex
+		containers  map[string]*container // by ID
+		images      map[string]*docker.Image
+		networks    map[string]*network
+		volumes     map[string]*docker.Volume
+		execs       map[string]*execInstance
+		failures    []*failureRule
+		requests    []Request
+		execHandler ExecHandler
+		nextPort    int
+		nextSubnet  int
+		lastSeq     int
+	}
+
+	// Failure - scripted error response (see (*Server).Fail).
+	Failure struct {
+		// Method - http method of the request (e.g. http.MethodPost), empty matches any method.
+		Method string
+		// Path - regexp matched against the request path without API version prefix,
+		// e.g. `^/containers/[^/]+/start$`. Empty matches any path.
+		Path string
+		// Status - http status code of the response (e.g. http.StatusInternalServerError).
+		Status int
+		// Message - error message of the response.
+		Message string
+		// Times - how many matching requests will fail, 0 means all of them.
+		Times int
+		// Delay - delays the response (e.g. to simulate hung image pull).
+		// The request is dropped if the client cancels it during the delay.
+		// Zero Status with Delay means the request is handled as usual after the delay.
+		Delay time.Duration
+	}
+
+	// Request - request received by the server (see (*Server).Requests).
+	Request struct {
+		Method string
+		// Path - request path without API version prefix (e.g. "/containers/create").
+		Path string
+		// APIVersion - version from the path prefix (e.g. "1.43"), empty for requests without version.
+		APIVersion string
+	}
+
+	failureRule struct {
+		Failure
+		path *regexp.Regexp
+	}
+)
+
+// NewServer - starts new fake server with empty state (only predefined "bridge", "host" and "none" networks).
+// Don't forget to Close it.
+func NewServer() *Server {
+	server := &Server{
+		httpServer:  nil,
+		mux:         http.NewServeMux(),
+		closed:      make(chan struct{}),
+		closeOnce:   sync.Once{},
+		mu:          sync.Mutex{},
+		containers:  map[string]*container{},
+		images:      map[string]*docker.Image{},
+		networks:    map[string]*network{},
+		volumes:     map[string]*docker.Volume{},
+		execs:       map[string]*execInstance{},
+		failures:    nil,
+		requests:    nil,
+		execHandler: defaultExecHandler,
+		nextPort:    firstHostPort,
+		nextSubnet:  0,
+		lastSeq:     0,
+	}
+
+	server.addNetwork(bridgeNetwork, bridgeNetwork, nil)
+	server.addNetwork(hostNetwork, hostNetwork, nil)
+	server.addNetwork(noneNetwork, nullNetworkDriver, nil)
+
+	server.routes()
+	server.httpServer = httptest.NewServer(server)
+
+	return server
+}
+
+// Close - shutdowns the server. Blocked requests (e.g. stop with timeout) are aborted.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.httpServer.CloseClientConnections()
+		s.httpServer.Close()
+	})
+}
+
+// URL - endpoint of the server, e.g. "http://127.0.0.1:12345".
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Client - returns new docker client connected to the server.
+func (s *Server) Client() *docker.Client {
+	client, err := docker.NewClient(s.URL())
+	if err != nil {
+		// impossible with the valid url of httptest server
+		panic(fmt.Errorf("failed to docker.NewClient: %w", err))
+	}
+
+	return client
+}
+
+// Fail - makes the server respond with the error (or with delay) to requests matching the failure.
+//   - Status is http.StatusInternalServerError by default, unless Delay is set.
+//   - Failures are checked in order of addition, before the request is handled (state isn't changed).
+//   - Panics if failure.Path isn't a valid regexp.
+func (s *Server) Fail(failure Failure) {
+	if failure.Status == 0 && failure.Delay == 0 {
+		failure.Status = http.StatusInternalServerError
+	}
+	if failure.Message == "" {
+		failure.Message = "dockerfake: injected failure"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &failureRule{Failure: failure, path: regexp.MustCompile(failure.Path)})
+}
+
+// ResetFailures - removes all failures added by Fail.
+func (s *Server) ResetFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = nil
+}
+
+// Requests - returns all requests received by the server in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// ServeHTTP - implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the client adds version prefix (e.g. "/v1.43/containers/json") when API version is set
+	apiVersion := ""
+	if prefix := apiVersionPrefixRegexp.FindString(r.URL.Path); prefix != "" {
+		apiVersion = strings.Trim(prefix, "/v")
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, prefix[:len(prefix)-1])
+		r.URL.RawPath = ""
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, APIVersion: apiVersion})
+	injected := s.matchFailure(r)
+	s.mu.Unlock()
+
+	if injected != nil {
+		s.wait(r, injected.Delay)
+		if r.Context().Err() != nil {
+			return // the client has gone
+		}
+
+		if injected.Status != 0 {
+			writeError(w, injected.Status, injected.Message)
+			return
+		}
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// matchFailure - returns the first failure matching the request and counts it. Requires s.mu.
+func (s *Server) matchFailure(r *http.Request) *failureRule {
+	for i, rule := range s.failures {
+		if rule.Method != "" && rule.Method != r.Method {
+			continue
+		}
+		if !rule.path.MatchString(r.URL.Path) {
+			continue
+		}
+
+		if rule.Times > 0 {
+			rule.Times--
+			if rule.Times == 0 {
+				s.failures = append(s.failures[:i:i], s.failures[i+1:]...)
+			}
+		}
+
+		return rule
+	}
+
+	return nil
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /_ping", s.handlePing)
+	s.mux.HandleFunc("HEAD /_ping", s.handlePing)
+	s.mux.HandleFunc("GET /version", s.handleVersion)
+
+	s.mux.HandleFunc("POST /containers/create", s.handleContainerCreate)
+	s.mux.HandleFunc("GET /containers/json", s.handleContainerList)
+	s.mux.HandleFunc("GET /containers/{id}/json", s.handleContainerInspect)
+	s.mux.HandleFunc("POST /containers/{id}/start", s.handleContainerStart)
+	s.mux.HandleFunc("POST /containers/{id}/stop", s.handleContainerStop)
+	s.mux.HandleFunc("POST /containers/{id}/restart", s.handleContainerRestart)
+	s.mux.HandleFunc("POST /containers/{id}/kill", s.handleContainerKill)
+	s.mux.HandleFunc("POST /containers/{id}/pause", s.handleContainerPause)
+	s.mux.HandleFunc("POST /containers/{id}/unpause", s.handleContainerUnpause)
+	s.mux.HandleFunc("DELETE /containers/{id}", s.handleContainerRemove)
+	s.mux.HandleFunc("GET /containers/{id}/logs", s.handleContainerLogs)
+	s.mux.HandleFunc("POST /containers/{id}/exec", s.handleExecCreate)
+	s.mux.HandleFunc("POST /exec/{id}/start", s.handleExecStart)
+	s.mux.HandleFunc("GET /exec/{id}/json", s.handleExecInspect)
+
+	s.mux.HandleFunc("GET /images/json", s.handleImageList)
+	s.mux.HandleFunc("POST /images/create", s.handleImagePull)
+	s.mux.HandleFunc("POST /build", s.handleImageBuild)
+	// image names may contain slashes (e.g. "localhost:5000/team/app:tag")
+	s.mux.HandleFunc("GET /images/{name...}", s.handleImageInspect)
+	s.mux.HandleFunc("POST /images/{name...}", s.handleImageTag)
+	s.mux.HandleFunc("DELETE /images/{name...}", s.handleImageRemove)
+
+	s.mux.HandleFunc("GET /networks", s.handleNetworkList)
+	s.mux.HandleFunc("GET /networks/{id}", s.handleNetworkInspect)
+	s.mux.HandleFunc("POST /networks/create", s.handleNetworkCreate)
+	s.mux.HandleFunc("DELETE /networks/{id}", s.handleNetworkRemove)
+	s.mux.HandleFunc("POST /networks/{id}/connect", s.handleNetworkConnect)
+	s.mux.HandleFunc("POST /networks/{id}/disconnect", s.handleNetworkDisconnect)
+
+	s.mux.HandleFunc("GET /volumes", s.handleVolumeList)
+	s.mux.HandleFunc("DELETE /volumes/{name}", s.handleVolumeRemove)
+
+	s.mux.HandleFunc("/", writeNotImplemented)
+}
+
+func (s *Server) handlePing(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Api-Version", APIVersion)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"Version":       "dockerfake",
+		"ApiVersion":    APIVersion,
+		"MinAPIVersion": minAPIVersion,
+		"Os":            "linux",
+		"Arch":          "amd64",
+	})
+}
+
+// wait - blocks for duration or until the request or server is closed.
+func (s *Server) wait(r *http.Request, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-r.Context().Done():
+	case <-s.closed:
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func decodeBody(r *http.Request, target any) (err error) {
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+
+	err = json.NewDecoder(r.Body).Decode(target)
+	if err != nil {
+		return fmt.Errorf("failed to decode request body: %w", err)
+	}
+
+	return nil
+}
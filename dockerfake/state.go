@@ -0,0 +1,500 @@
+package dockerfake
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	bridgeNetwork     = "bridge"
+	hostNetwork       = "host"
+	noneNetwork       = "none"
+	nullNetworkDriver = "null"
+
+	imageIDPrefix = "sha256:"
+	shortIDLength = 12
+	idBytes       = 32
+	latestTag     = "latest"
+	firstHostPort = 32768
+)
+
+type (
+	// ExecHandler - returns scripted result of the command executed in the container (see (*Server).SetExecHandler).
+	ExecHandler func(container docker.Container, cmd []string) ExecResult
+
+	// ExecResult - output and exit code of the command executed in the container.
+	ExecResult struct {
+		ExitCode int
+		Stdout   string
+		Stderr   string
+	}
+
+	container struct {
+		docker.Container
+		seq     int
+		volumes []string
+		logs    []logEntry
+	}
+
+	logEntry struct {
+		stderr bool
+		data   []byte
+	}
+
+	network struct {
+		docker.Network
+		subnet int
+		lastIP int
+	}
+
+	execInstance struct {
+		docker.ExecInspect
+		cmd []string
+	}
+)
+
+// defaultExecHandler - every command succeeds without output.
+func defaultExecHandler(docker.Container, []string) ExecResult {
+	return ExecResult{ExitCode: 0, Stdout: "", Stderr: ""}
+}
+
+// SetExecHandler - sets handler that produces results of commands executed in containers
+// (by default every command succeeds without output). Nil handler restores the default one.
+func (s *Server) SetExecHandler(handler ExecHandler) {
+	if handler == nil {
+		handler = defaultExecHandler
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.execHandler = handler
+}
+
+// AddImage - adds local image with the ref (e.g. "busybox" or "busybox:1.36") and labels,
+// as if it was pulled or built. Ref is moved from the image that already has it.
+func (s *Server) AddImage(ref string, labels map[string]string) docker.Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return clone(*s.addImage([]string{ref}, labels))
+}
+
+// Image - returns image by name or ID.
+func (s *Server) Image(name string) (image docker.Image, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := s.findImage(name)
+	if found == nil {
+		return docker.Image{}, false //nolint:exhaustruct
+	}
+
+	return clone(*found), true
+}
+
+// Images - returns all images.
+func (s *Server) Images() []docker.Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	images := make([]docker.Image, 0, len(s.images))
+	for _, image := range s.images {
+		images = append(images, clone(*image))
+	}
+	slices.SortFunc(images, func(a, b docker.Image) int { return a.Created.Compare(b.Created) })
+
+	return images
+}
+
+// Container - returns container by name or ID.
+func (s *Server) Container(idOrName string) (container docker.Container, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := s.findContainer(idOrName)
+	if found == nil {
+		return docker.Container{}, false //nolint:exhaustruct
+	}
+
+	return clone(found.Container), true
+}
+
+// Containers - returns all containers in order of creation.
+func (s *Server) Containers() []docker.Container {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	containers := make([]docker.Container, 0, len(s.containers))
+	for _, container := range s.sortedContainers() {
+		containers = append(containers, clone(container.Container))
+	}
+
+	return containers
+}
+
+// UpdateContainer - changes container state in place, e.g. to simulate the container killed by OOM killer:
+//
+//	server.UpdateContainer(name, func(container *docker.Container) {
+//		container.State.Running = false
+//		container.State.OOMKilled = true
+//		container.State.Status = "exited"
+//	})
+//
+// Returns *docker.NoSuchContainer error if container doesn't exist.
+func (s *Server) UpdateContainer(idOrName string, update func(container *docker.Container)) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := s.findContainer(idOrName)
+	if found == nil {
+		return &docker.NoSuchContainer{ID: idOrName, Err: nil}
+	}
+
+	update(&found.Container)
+
+	return nil
+}
+
+// AppendLogs - appends output of the container, that will be returned by logs request.
+// Returns *docker.NoSuchContainer error if container doesn't exist.
+func (s *Server) AppendLogs(idOrName string, stdout, stderr string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := s.findContainer(idOrName)
+	if found == nil {
+		return &docker.NoSuchContainer{ID: idOrName, Err: nil}
+	}
+
+	if stdout != "" {
+		found.logs = append(found.logs, logEntry{stderr: false, data: []byte(stdout)})
+	}
+	if stderr != "" {
+		found.logs = append(found.logs, logEntry{stderr: true, data: []byte(stderr)})
+	}
+
+	return nil
+}
+
+// Network - returns network by name or ID.
+func (s *Server) Network(idOrName string) (network docker.Network, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := s.findNetwork(idOrName)
+	if found == nil {
+		return docker.Network{}, false //nolint:exhaustruct
+	}
+
+	return clone(found.Network), true
+}
+
+// Networks - returns all networks including predefined ones.
+func (s *Server) Networks() []docker.Network {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	networks := make([]docker.Network, 0, len(s.networks))
+	for _, network := range s.sortedNetworks() {
+		networks = append(networks, clone(network.Network))
+	}
+
+	return networks
+}
+
+// AddVolume - adds volume with the name and labels, does nothing if it already exists.
+func (s *Server) AddVolume(name string, labels map[string]string) docker.Volume {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return clone(*s.addVolume(name, labels))
+}
+
+// Volumes - returns all volumes sorted by name.
+func (s *Server) Volumes() []docker.Volume {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	volumes := make([]docker.Volume, 0, len(s.volumes))
+	for _, volume := range s.volumes {
+		volumes = append(volumes, clone(*volume))
+	}
+	slices.SortFunc(volumes, func(a, b docker.Volume) int { return strings.Compare(a.Name, b.Name) })
+
+	return volumes
+}
+
+// addImage - creates new image with refs (moved from other images). Requires s.mu.
+func (s *Server) addImage(refs []string, labels map[string]string) *docker.Image {
+	image := &docker.Image{ //nolint:exhaustruct
+		ID:           imageIDPrefix + newID(),
+		Created:      time.Now(),
+		Config:       &docker.Config{Labels: labels}, //nolint:exhaustruct
+		Architecture: "amd64",
+		OS:           "linux",
+	}
+	s.images[image.ID] = image
+
+	for _, ref := range refs {
+		s.tagImage(image, ref)
+	}
+
+	return image
+}
+
+// tagImage - adds ref to the image, removes it from other images. Requires s.mu.
+func (s *Server) tagImage(image *docker.Image, ref string) {
+	ref = normalizeRef(ref)
+
+	for _, other := range s.images {
+		other.RepoTags = slices.DeleteFunc(other.RepoTags, func(tag string) bool { return tag == ref })
+	}
+
+	image.RepoTags = append(image.RepoTags, ref)
+}
+
+// findImage - finds image by ID, ID prefix or ref. Requires s.mu.
+func (s *Server) findImage(name string) *docker.Image {
+	if image, ok := s.images[name]; ok {
+		return image
+	}
+
+	ref := normalizeRef(name)
+	for _, image := range s.images {
+		if slices.Contains(image.RepoTags, ref) {
+			return image
+		}
+	}
+
+	idPrefix := imageIDPrefix + strings.TrimPrefix(name, imageIDPrefix)
+	if !isHex(strings.TrimPrefix(idPrefix, imageIDPrefix)) {
+		return nil
+	}
+
+	var found *docker.Image
+	for id, image := range s.images {
+		if strings.HasPrefix(id, idPrefix) {
+			if found != nil {
+				return nil // ambiguous
+			}
+			found = image
+		}
+	}
+
+	return found
+}
+
+// findContainer - finds container by ID, name or ID prefix. Requires s.mu.
+func (s *Server) findContainer(idOrName string) *container {
+	if container, ok := s.containers[idOrName]; ok {
+		return container
+	}
+
+	name := "/" + strings.TrimPrefix(idOrName, "/")
+	for _, container := range s.containers {
+		if container.Name == name {
+			return container
+		}
+	}
+
+	if !isHex(idOrName) {
+		return nil
+	}
+
+	var found *container
+	for id, container := range s.containers {
+		if strings.HasPrefix(id, idOrName) {
+			if found != nil {
+				return nil // ambiguous
+			}
+			found = container
+		}
+	}
+
+	return found
+}
+
+// findNetwork - finds network by ID, name or ID prefix. Requires s.mu.
+func (s *Server) findNetwork(idOrName string) *network {
+	if network, ok := s.networks[idOrName]; ok {
+		return network
+	}
+
+	for _, network := range s.networks {
+		if network.Name == idOrName {
+			return network
+		}
+	}
+
+	if !isHex(idOrName) {
+		return nil
+	}
+
+	var found *network
+	for id, network := range s.networks {
+		if strings.HasPrefix(id, idOrName) {
+			if found != nil {
+				return nil // ambiguous
+			}
+			found = network
+		}
+	}
+
+	return found
+}
+
+// addNetwork - creates new network. Requires s.mu or not started server.
+func (s *Server) addNetwork(name, driver string, labels map[string]string) *network {
+	created := &network{
+		Network: docker.Network{ //nolint:exhaustruct
+			Name:       name,
+			ID:         newID(),
+			Scope:      "local",
+			Driver:     driver,
+			Containers: map[string]docker.Endpoint{},
+			Options:    map[string]string{},
+			Labels:     labels,
+		},
+		subnet: s.nextSubnet,
+		lastIP: 1, // gateway
+	}
+	if driver == bridgeNetwork {
+		created.IPAM = docker.IPAMOptions{ //nolint:exhaustruct
+			Driver: "default",
+			Config: []docker.IPAMConfig{{Subnet: created.subnetCIDR(), Gateway: created.gateway()}}, //nolint:exhaustruct
+		}
+		s.nextSubnet++
+	}
+	s.networks[created.ID] = created
+
+	return created
+}
+
+// addVolume - creates volume if it doesn't exist. Requires s.mu.
+func (s *Server) addVolume(name string, labels map[string]string) *docker.Volume {
+	if volume, ok := s.volumes[name]; ok {
+		return volume
+	}
+
+	volume := &docker.Volume{ //nolint:exhaustruct
+		Name:       name,
+		Driver:     "local",
+		Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+		Labels:     labels,
+	}
+	s.volumes[name] = volume
+
+	return volume
+}
+
+// nextSeq - returns sequence number for ordering by creation. Requires s.mu.
+func (s *Server) nextSeq() int {
+	s.lastSeq++
+
+	return s.lastSeq
+}
+
+// sortedContainers - returns containers in order of creation. Requires s.mu.
+func (s *Server) sortedContainers() []*container {
+	containers := make([]*container, 0, len(s.containers))
+	for _, container := range s.containers {
+		containers = append(containers, container)
+	}
+	slices.SortFunc(containers, func(a, b *container) int { return a.seq - b.seq })
+
+	return containers
+}
+
+// sortedNetworks - returns networks sorted by name. Requires s.mu.
+func (s *Server) sortedNetworks() []*network {
+	networks := make([]*network, 0, len(s.networks))
+	for _, network := range s.networks {
+		networks = append(networks, network)
+	}
+	slices.SortFunc(networks, func(a, b *network) int { return strings.Compare(a.Name, b.Name) })
+
+	return networks
+}
+
+func (n *network) isPredefined() bool {
+	return n.Name == bridgeNetwork || n.Name == hostNetwork || n.Name == noneNetwork
+}
+
+// hasAddresses - containers connected to the network get ip addresses (host and none networks don't).
+func (n *network) hasAddresses() bool {
+	return n.Driver == bridgeNetwork
+}
+
+func (n *network) subnetCIDR() string {
+	return fmt.Sprintf("172.%d.0.0/16", 17+n.subnet) //nolint:mnd
+}
+
+func (n *network) gateway() string {
+	return fmt.Sprintf("172.%d.0.1", 17+n.subnet) //nolint:mnd
+}
+
+// allocateIP - returns next free address of the network.
+func (n *network) allocateIP() string {
+	n.lastIP++
+
+	return fmt.Sprintf("172.%d.%d.%d", 17+n.subnet, n.lastIP/256, n.lastIP%256) //nolint:mnd
+}
+
+// normalizeRef - adds "latest" tag to the ref without tag or digest ("busybox" -> "busybox:latest").
+func normalizeRef(ref string) string {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	if strings.ContainsAny(name, ":@") {
+		return ref
+	}
+
+	return ref + ":" + latestTag
+}
+
+func newID() string {
+	id := make([]byte, idBytes)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, imageIDPrefix)
+	if len(id) > shortIDLength {
+		return id[:shortIDLength]
+	}
+
+	return id
+}
+
+func isHex(value string) bool {
+	if value == "" {
+		return false
+	}
+	_, err := hex.DecodeString(value + value[:len(value)%2])
+
+	return err == nil
+}
+
+// clone - returns deep copy of the value, so callers can't change the server state.
+func clone[T any](value T) T {
+	var cloned T
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Errorf("failed to json.Marshal: %w", err))
+	}
+	err = json.Unmarshal(data, &cloned)
+	if err != nil {
+		panic(fmt.Errorf("failed to json.Unmarshal: %w", err))
+	}
+
+	return cloned
+}
@@ -0,0 +1,5 @@
+package tcontainer
+
+// PoolForTestWithCheck - poolForTest for the tests of tcontainer_test package
+// (they can use generated mocks that import tcontainer).
+var PoolForTestWithCheck = poolForTest //nolint:gochecknoglobals
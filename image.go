@@ -0,0 +1,165 @@
+package tcontainer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// TagImage - adds reference (e.g. "localhost:5000/my/app:test") to the image built by Build or BuildAndGet.
+//   - image - image returned by BuildAndGet, it's found by ImageLabelUUID label,
+//     so ref can't point to the stale image with the same name.
+//   - If ref has no tag - "latest" will be used.
+//   - Rewrites ref if it's already used by another image.
+func (p Pool) TagImage(ctx context.Context, image *docker.Image, ref string) (err error) {
+	repository, tag, err := parseImageRef(ref)
+	if err != nil {
+		return fmt.Errorf("failed to parseImageRef: %w", err)
+	}
+
+	builtImage, err := p.findBuiltImage(ctx, image)
+	if err != nil {
+		return fmt.Errorf("failed to findBuiltImage: %w", err)
+	}
+
+	err = p.Pool.Client.TagImage(builtImage.ID, docker.TagImageOptions{
+		Repo:    repository,
+		Tag:     tag,
+		Force:   true,
+		Context: ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to TagImage: %w", err)
+	}
+
+	return nil
+}
+
+// PushImage - pushes image by ref (e.g. "localhost:5000/my/app:test") to the registry from the ref.
+//   - Use TagImage to add registry reference to the image built by Build or BuildAndGet before push.
+//   - Use empty auth for registries without authentication (e.g. local registry:2 container).
+func (p Pool) PushImage(ctx context.Context, ref string, auth docker.AuthConfiguration) (err error) {
+	repository, tag, err := parseImageRef(ref)
+	if err != nil {
+		return fmt.Errorf("failed to parseImageRef: %w", err)
+	}
+
+	err = p.Pool.Client.PushImage(docker.PushImageOptions{
+		Name:              repository,
+		Tag:               tag,
+		Registry:          "",
+		OutputStream:      io.Discard,
+		RawJSONStream:     false,
+		InactivityTimeout: 0,
+		Context:           ctx,
+	}, auth)
+	if err != nil {
+		return fmt.Errorf("failed to PushImage: %w", err)
+	}
+
+	return nil
+}
+
+// SaveImage - writes tar archive with images by refs (names or IDs) to w.
+// The archive could be loaded back by LoadImage or `docker load`.
+//   - Use TagImage to name the image built by Build or BuildAndGet, images saved by ID are loaded without name.
+func (p Pool) SaveImage(ctx context.Context, w io.Writer, refs ...string) (err error) {
+	if len(refs) == 0 {
+		return fmt.Errorf("%w: at least one image ref is required", ErrInvalidOptions)
+	}
+	if slices.Contains(refs, "") {
+		return fmt.Errorf("%w: image ref is empty", ErrInvalidOptions)
+	}
+	if w == nil {
+		return fmt.Errorf("%w: writer is nil", ErrInvalidOptions)
+	}
+
+	err = p.Pool.Client.ExportImages(docker.ExportImagesOptions{
+		Names:             refs,
+		OutputStream:      w,
+		InactivityTimeout: 0,
+		Context:           ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ExportImages: %w", err)
+	}
+
+	return nil
+}
+
+// LoadImage - loads images from tar archive created by SaveImage or `docker save`.
+func (p Pool) LoadImage(ctx context.Context, r io.Reader) (err error) {
+	if r == nil {
+		return fmt.Errorf("%w: reader is nil", ErrInvalidOptions)
+	}
+
+	err = p.Pool.Client.LoadImage(docker.LoadImageOptions{
+		InputStream:  r,
+		OutputStream: io.Discard,
+		Context:      ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to LoadImage: %w", err)
+	}
+
+	return nil
+}
+
+// parseImageRef - validates ref and splits it to repository and tag ("latest" by default).
+func parseImageRef(ref string) (repository, tag string, err error) {
+	if ref == "" {
+		return "", "", fmt.Errorf("%w: image ref is required", ErrInvalidOptions)
+	}
+	if !imageReferenceRegexp.MatchString(ref) {
+		return "", "", fmt.Errorf("%w: `%s` is not a valid image reference", ErrInvalidOptions, ref)
+	}
+
+	repository, tag = docker.ParseRepositoryTag(ref)
+	if tag == "" {
+		tag = defaultImageTag
+	}
+
+	return repository, tag, nil
+}
+
+// findBuiltImage - finds actual state of the image built by Build or BuildAndGet by its ImageLabelUUID label.
+func (p Pool) findBuiltImage(ctx context.Context, image *docker.Image) (builtImage docker.APIImages, err error) {
+	if image == nil {
+		return docker.APIImages{}, fmt.Errorf("%w: image is required", ErrInvalidOptions)
+	}
+
+	var imageUUID string
+	if image.Config != nil {
+		imageUUID = image.Config.Labels[ImageLabelUUID]
+	}
+	if imageUUID == "" {
+		return docker.APIImages{}, fmt.Errorf(
+			"%w: image `%s` has no `%s` label, use image built by Build or BuildAndGet",
+			ErrInvalidOptions, image.ID, ImageLabelUUID,
+		)
+	}
+
+	builtImage, err = p.findImageByUUID(ctx, imageUUID)
+	if err != nil {
+		return docker.APIImages{}, fmt.Errorf("failed to findImageByUUID: %w", err)
+	}
+
+	return builtImage, nil
+}
+
+// inspectImage - InspectImage with ctx, returns docker.ErrNoSuchImage if the image doesn't exist locally.
+func (p Pool) inspectImage(ctx context.Context, name string) (image *docker.Image, err error) {
+	image = &docker.Image{} //nolint:exhaustruct
+	err = dockerRequest(ctx, p.Pool.Client, http.MethodGet, "", "/images/"+name+"/json", nil, image)
+	if isDockerErrorStatus(err, http.StatusNotFound) {
+		return nil, docker.ErrNoSuchImage
+	} else if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
@@ -0,0 +1,154 @@
+package tcontainer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRegistry - runs local registry:2 container, returns it's address accessible by docker daemon.
+func runRegistry(ctx context.Context, pool Pool, customOpts ...RunOption) (container *Container, address string, err error) {
+	const registryPort = "5000"
+	registryAddress := func(container *Container) string {
+		return "localhost:" + container.GetPort(registryPort+"/tcp")
+	}
+
+	opts := append([]RunOption{
+		WithRandomHostPort(registryPort),
+		func(options *RunOptions) (err error) {
+			options.Tag = "2"
+			options.Retry.Operation = func(ctx context.Context, container *Container) (err error) {
+				url := "http://" + registryAddress(container) + "/v2/"
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+				if err != nil {
+					return fmt.Errorf("failed to http.NewRequestWithContext: %w", err)
+				}
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					return fmt.Errorf("failed to http.Do: %w", err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode != http.StatusOK {
+					return fmt.Errorf("unexpected response status `%s`", resp.Status)
+				}
+
+				return nil
+			}
+			return nil
+		},
+	}, customOpts...)
+
+	container, err = pool.Run(ctx, "registry", opts...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to Run: %w", err)
+	}
+
+	return container, registryAddress(container), nil
+}
+
+func Test_TagImage(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	pool := MustNewPool("")
+
+	image, err := buildTestImage(pool)
+	require.NoError(err)
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveImageExtended(image.ID, docker.RemoveImageOptions{Force: true}) })
+
+	err = pool.TagImage(context.Background(), image, "tcontainer/test_tag_image")
+	require.NoError(err)
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveImage("tcontainer/test_tag_image:latest") })
+
+	taggedImage, err := pool.Pool.Client.InspectImage("tcontainer/test_tag_image:latest")
+	require.NoError(err)
+	require.Equal(image.ID, taggedImage.ID)
+
+	// invalid ref
+	err = pool.TagImage(context.Background(), image, "Invalid/Ref")
+	require.ErrorIs(err, ErrInvalidOptions)
+
+	// image not built by Build / BuildAndGet
+	err = pool.TagImage(context.Background(), &docker.Image{ID: image.ID}, "tcontainer/test_tag_image")
+	require.ErrorIs(err, ErrInvalidOptions)
+	err = pool.TagImage(context.Background(), nil, "tcontainer/test_tag_image")
+	require.ErrorIs(err, ErrInvalidOptions)
+}
+
+func Test_PushImage(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := MustNewPool("")
+
+	registry, registryAddress, err := runRegistry(context.Background(), pool)
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(registry.Close()) })
+
+	image, err := buildTestImage(pool)
+	require.NoError(err)
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveImageExtended(image.ID, docker.RemoveImageOptions{Force: true}) })
+
+	ref := registryAddress + "/tcontainer/test_push_image:v1"
+	require.NoError(pool.TagImage(context.Background(), image, ref))
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveImage(ref) })
+	err = pool.PushImage(context.Background(), ref, docker.AuthConfiguration{})
+	require.NoError(err)
+
+	// check image is available in the registry
+	req, err := http.NewRequestWithContext(
+		context.Background(), http.MethodGet, "http://"+registryAddress+"/v2/tcontainer/test_push_image/tags/list", nil,
+	)
+	require.NoError(err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	tags := struct {
+		Tags []string `json:"tags"`
+	}{}
+	require.NoError(json.NewDecoder(resp.Body).Decode(&tags))
+	require.True(slices.Contains(tags.Tags, "v1"), "pushed tag not found: %v", tags.Tags)
+}
+
+func Test_SaveImage_LoadImage(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	pool := MustNewPool("")
+
+	const ref = "tcontainer/test_save_image:latest"
+	image, err := buildTestImage(pool, WithImageName(ref))
+	require.NoError(err)
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveImageExtended(ref, docker.RemoveImageOptions{Force: true}) })
+
+	// save
+	archive := &bytes.Buffer{}
+	require.NoError(pool.SaveImage(context.Background(), archive, ref))
+	require.NotZero(archive.Len())
+
+	// remove and load back
+	require.NoError(pool.Pool.Client.RemoveImageExtended(ref, docker.RemoveImageOptions{Force: true}))
+	require.NoError(pool.LoadImage(context.Background(), archive))
+
+	loadedImage, err := pool.Pool.Client.InspectImage(ref)
+	require.NoError(err)
+	require.Equal(image.ID, loadedImage.ID)
+
+	// invalid options
+	require.ErrorIs(pool.SaveImage(context.Background(), archive), ErrInvalidOptions)
+	require.ErrorIs(pool.SaveImage(context.Background(), archive, ""), ErrInvalidOptions)
+	require.ErrorIs(pool.SaveImage(context.Background(), nil, ref), ErrInvalidOptions)
+	require.ErrorIs(pool.LoadImage(context.Background(), nil), ErrInvalidOptions)
+}
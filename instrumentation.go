@@ -0,0 +1,101 @@
+package tcontainer
+
+import (
+	"context"
+)
+
+// Instrumented operations (see Instrumentation).
+const (
+	// OperationRun - Pool.Run, from options to the ready container.
+	OperationRun Operation = "tcontainer.run"
+	// OperationBuild - Pool.Build and Pool.BuildAndGet.
+	OperationBuild Operation = "tcontainer.build"
+	// OperationPrune - Pool.Prune.
+	OperationPrune Operation = "tcontainer.prune"
+	// OperationPull - StartupPhasePull of the Run.
+	OperationPull Operation = "tcontainer.pull"
+	// OperationCreate - StartupPhaseCreate of the Run (creation of the container and AfterCreate hooks).
+	OperationCreate Operation = "tcontainer.create"
+	// OperationStart - StartupPhaseStart of the Run (start of the created container).
+	OperationStart Operation = "tcontainer.start"
+	// OperationReady - StartupPhaseReady of the Run (readiness retries and AfterReady hooks).
+	OperationReady Operation = "tcontainer.ready"
+)
+
+type (
+	// Operation - name of the instrumented operation of the Pool.
+	Operation string
+
+	// Instrumentation - receives operations of the Pool for tracing and metrics (see Pool.WithInstrumentation()).
+	// See tcontainerotel module for OpenTelemetry implementation.
+	Instrumentation interface {
+		// Start - called at the start of the operation, returned ctx is used by the operation
+		// (e.g. ctx with the span, so nested phases of the Run are children of the Run span).
+		// The returned end is called once with the result of the operation.
+		Start(ctx context.Context, operation Operation, info OperationInfo) (opCtx context.Context, end OperationEnd)
+		// RetryAttempt - called after each failed attempt of the readiness check (see WithRetry()),
+		// ctx is ctx of the OperationReady.
+		RetryAttempt(ctx context.Context, info OperationInfo, attempt int, err error)
+	}
+
+	// OperationEnd - finishes the operation started by Instrumentation.Start.
+	OperationEnd func(result OperationResult)
+
+	// OperationInfo - attributes of the operation, empty if not applicable to the operation.
+	OperationInfo struct {
+		// ContainerName - name of the container (see WithContainerName()).
+		ContainerName string
+		// Image - image of the container or name of the built image.
+		Image string
+	}
+
+	// OperationResult - result of the operation.
+	OperationResult struct {
+		// Err - error of the operation.
+		Err error
+		// ContainerID - ID of the container (only successful OperationRun).
+		ContainerID string
+		// Origin - origin of the container (only OperationRun), e.g. reused or recreated container.
+		Origin ContainerOrigin
+	}
+)
+
+// WithInstrumentation - returns derived Pool that reports its operations to the instrumentation
+// (Run, Build, Prune and startup phases of the Run). Nil instrumentation disables reporting.
+//   - The parent Pool isn't changed.
+//
+// Example usage:
+//
+//	instrumentation, err := tcontainerotel.New(tcontainerotel.WithTracerProvider(tracerProvider))
+//	pool = pool.WithInstrumentation(instrumentation)
+func (p Pool) WithInstrumentation(instrumentation Instrumentation) Pool {
+	p.instrumentation = instrumentation
+	return p
+}
+
+// instrument - returns instrumentation of the pool, does nothing if there is no instrumentation.
+func (p Pool) instrument() Instrumentation {
+	if p.instrumentation == nil {
+		return noopInstrumentation{}
+	}
+
+	return p.instrumentation
+}
+
+// operationInfo - returns attributes of the container for the instrumentation.
+func (o RunOptions) operationInfo() OperationInfo {
+	return OperationInfo{ContainerName: o.Name, Image: o.image()}
+}
+
+// phaseOperation - returns operation of the startup phase.
+func phaseOperation(phase StartupPhase) Operation {
+	return Operation("tcontainer." + phase)
+}
+
+type noopInstrumentation struct{}
+
+func (noopInstrumentation) Start(ctx context.Context, _ Operation, _ OperationInfo) (context.Context, OperationEnd) {
+	return ctx, func(OperationResult) {}
+}
+
+func (noopInstrumentation) RetryAttempt(context.Context, OperationInfo, int, error) {}
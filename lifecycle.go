@@ -0,0 +1,227 @@
+package tcontainer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// Stop - gracefully stops the container by the stop signal of the image (kills it after StopTimeout),
+// does nothing if it's not running.
+//   - container.Container is refreshed.
+//   - Container with AutoRemove (default) is removed by docker after stop and can't be started again,
+//     use WithHostConfig to disable AutoRemove if you want to Start it again.
+func (p Pool) Stop(ctx context.Context, container *Container, customOpts ...LifecycleOption) (err error) {
+	options, err := applyLifecycleOptions(container, customOpts...)
+	if err != nil {
+		return err
+	}
+
+	err = p.Pool.Client.StopContainerWithContext(container.Container.ID, uint(options.StopTimeout.Seconds()), ctx)
+	if err != nil && !errors.As(err, ptr((*docker.ContainerNotRunning)(nil))) {
+		return fmt.Errorf("failed to StopContainer: %w", err)
+	}
+
+	return p.refreshStoppedContainer(ctx, container.Resource)
+}
+
+// Start - starts stopped container, does nothing if it's already running.
+//   - container.Container is refreshed (e.g. new host ports for GetHostEndpoints).
+//   - Reruns `Retry.Operation` of the container options, use WithRetryAfterStart to override it.
+func (p Pool) Start(ctx context.Context, container *Container, customOpts ...LifecycleOption) (err error) {
+	options, err := applyLifecycleOptions(container, customOpts...)
+	if err != nil {
+		return err
+	}
+
+	err = p.startContainer(ctx, container.Container.ID)
+	if err != nil {
+		return fmt.Errorf("failed to startContainer: %w", err)
+	}
+
+	return p.refreshAndRetry(ctx, container, options)
+}
+
+// Restart - stops (kills it after StopTimeout) and starts the container.
+//   - container.Container is refreshed (e.g. new host ports for GetHostEndpoints).
+//   - Reruns `Retry.Operation` of the container options, use WithRetryAfterStart to override it.
+func (p Pool) Restart(ctx context.Context, container *Container, customOpts ...LifecycleOption) (err error) {
+	options, err := applyLifecycleOptions(container, customOpts...)
+	if err != nil {
+		return err
+	}
+
+	// restart of the container with AutoRemove doesn't remove it, unlike Stop and Start
+	query := url.Values{"t": {strconv.Itoa(int(options.StopTimeout.Seconds()))}}
+	path := "/containers/" + container.Container.ID + "/restart"
+	err = dockerRequest(ctx, p.Pool.Client, http.MethodPost, "", path, query, nil)
+	if err != nil {
+		return fmt.Errorf("failed to RestartContainer: %w", err)
+	}
+
+	return p.refreshAndRetry(ctx, container, options)
+}
+
+// Pause - suspends all processes in the container.
+//   - container.Container is refreshed.
+func (p Pool) Pause(ctx context.Context, container *Container, customOpts ...LifecycleOption) (err error) {
+	_, err = applyLifecycleOptions(container, customOpts...)
+	if err != nil {
+		return err
+	}
+
+	err = dockerRequest(ctx, p.Pool.Client, http.MethodPost, "", "/containers/"+container.Container.ID+"/pause", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to PauseContainer: %w", err)
+	}
+
+	return p.refreshContainer(ctx, container.Resource)
+}
+
+// Unpause - resumes all processes in the paused container.
+//   - container.Container is refreshed.
+//   - Reruns `Retry.Operation` of the container options, use WithRetryAfterStart to override it.
+func (p Pool) Unpause(ctx context.Context, container *Container, customOpts ...LifecycleOption) (err error) {
+	options, err := applyLifecycleOptions(container, customOpts...)
+	if err != nil {
+		return err
+	}
+
+	err = p.unpauseContainer(ctx, container.Container.ID)
+	if err != nil {
+		return fmt.Errorf("failed to unpauseContainer: %w", err)
+	}
+
+	return p.refreshAndRetry(ctx, container, options)
+}
+
+// Kill - sends signal (docker.SIGKILL by default, see WithSignal) to the container.
+//   - container.Container is refreshed.
+//   - Container with AutoRemove (default) is removed by docker after it exits and can't be started again,
+//     use WithHostConfig to disable AutoRemove if you want to Start it again.
+func (p Pool) Kill(ctx context.Context, container *Container, customOpts ...LifecycleOption) (err error) {
+	options, err := applyLifecycleOptions(container, customOpts...)
+	if err != nil {
+		return err
+	}
+
+	err = p.Pool.Client.KillContainer(docker.KillContainerOptions{
+		ID:      container.Container.ID,
+		Signal:  options.Signal,
+		Context: ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to KillContainer: %w", err)
+	}
+
+	return p.refreshStoppedContainer(ctx, container.Resource)
+}
+
+// applyLifecycleOptions - applies customOpts over the readiness check from the container options.
+func applyLifecycleOptions(
+	container *Container, customOpts ...LifecycleOption,
+) (options LifecycleOptions, err error) {
+	if container == nil || container.Resource == nil || container.Container == nil {
+		return LifecycleOptions{}, fmt.Errorf("%w: container is required", ErrInvalidOptions)
+	}
+
+	if container.options.Retry.Operation != nil {
+		// backoff is stateful - the one used by Run isn't shared
+		retryBackoff := cloneBackOff(container.options.Retry.Backoff)
+		customOpts = append(
+			[]LifecycleOption{WithRetryAfterStart(container.options.Retry.Operation, retryBackoff)},
+			customOpts...,
+		)
+	}
+
+	options, err = ApplyLifecycleOptions(customOpts...)
+	if err != nil {
+		return LifecycleOptions{}, fmt.Errorf("failed to ApplyLifecycleOptions: %w", err)
+	}
+
+	return options, nil
+}
+
+// cloneBackOff - returns reset copy of the known backoff implementations, resets and returns others as is.
+func cloneBackOff(original backoff.BackOff) (clone backoff.BackOff) {
+	switch original := original.(type) {
+	case *backoff.ExponentialBackOff:
+		clone := *original
+		clone.Reset()
+		return &clone
+	case *backoff.ConstantBackOff:
+		clone := *original
+		return &clone
+	case nil:
+		return nil
+	default:
+		original.Reset()
+		return original
+	}
+}
+
+// startContainer - starts the container, does nothing if it's already running.
+func (p Pool) startContainer(ctx context.Context, containerID string) (err error) {
+	err = p.Pool.Client.StartContainerWithContext(containerID, nil, ctx)
+	if err != nil && !errors.As(err, ptr((*docker.ContainerAlreadyRunning)(nil))) {
+		return fmt.Errorf("failed to StartContainer: %w", err)
+	}
+
+	return nil
+}
+
+// unpauseContainer - unpauses the container.
+func (p Pool) unpauseContainer(ctx context.Context, containerID string) (err error) {
+	err = dockerRequest(ctx, p.Pool.Client, http.MethodPost, "", "/containers/"+containerID+"/unpause", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to UnpauseContainer: %w", err)
+	}
+
+	return nil
+}
+
+// refreshContainer - updates container.Container with actual state (e.g. network settings, host ports).
+func (p Pool) refreshContainer(ctx context.Context, container *dockertest.Resource) (err error) {
+	container.Container, err = p.inspectContainer(ctx, container.Container.ID)
+	if err != nil {
+		return fmt.Errorf("failed to inspectContainer: %w", err)
+	}
+
+	return nil
+}
+
+// refreshStoppedContainer - like refreshContainer, but ignores container removed by AutoRemove.
+func (p Pool) refreshStoppedContainer(ctx context.Context, container *dockertest.Resource) (err error) {
+	inspectedContainer, err := p.inspectContainer(ctx, container.Container.ID)
+	switch {
+	case err == nil:
+		container.Container = inspectedContainer
+		return nil
+	case errors.As(err, ptr((*docker.NoSuchContainer)(nil))):
+		container.Container.State.Running = false
+		return nil
+	default:
+		return fmt.Errorf("failed to inspectContainer: %w", err)
+	}
+}
+
+func (p Pool) refreshAndRetry(ctx context.Context, container *Container, options LifecycleOptions) (err error) {
+	err = p.refreshContainer(ctx, container.Resource)
+	if err != nil {
+		return err
+	}
+
+	err = p.retry(ctx, container, options.Retry)
+	if err != nil {
+		return fmt.Errorf("failed to retry: %w", err)
+	}
+
+	return nil
+}
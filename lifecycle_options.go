@@ -0,0 +1,126 @@
+package tcontainer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	defaultStopTimeout = time.Second * 10
+	defaultKillSignal  = docker.SIGKILL
+)
+
+type (
+	// LifecycleOptions for (Pool).Stop/Start/Restart/Pause/Unpause/Kill functions.
+	LifecycleOptions struct {
+		// StopTimeout - time to wait for graceful stop before kill (Stop, Restart).
+		StopTimeout time.Duration
+		// Signal - signal to send (Kill).
+		Signal docker.Signal
+		// Retry - readiness check after Start, Restart and Unpause.
+		// `Retry.Operation` of the container options by default, does nothing if Operation is nil.
+		Retry RetryOptions
+	}
+
+	// LifecycleOption - option for (Pool).Stop/Start/Restart/Pause/Unpause/Kill functions.
+	// See [ApplyLifecycleOptions].
+	LifecycleOption func(options *LifecycleOptions) (err error)
+)
+
+// WithStopTimeout - time to wait for graceful stop before kill (10s by default, rounded down to seconds).
+func WithStopTimeout(timeout time.Duration) LifecycleOption {
+	return func(options *LifecycleOptions) (err error) {
+		if timeout < 0 {
+			return fmt.Errorf("%w: stop timeout can't be negative", ErrInvalidOptions)
+		}
+
+		options.StopTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithSignal - signal to send by Kill (e.g. docker.SIGTERM), docker.SIGKILL by default.
+func WithSignal(signal docker.Signal) LifecycleOption {
+	return func(options *LifecycleOptions) (err error) {
+		if signal <= 0 {
+			return fmt.Errorf("%w: invalid signal `%d`", ErrInvalidOptions, signal)
+		}
+
+		options.Signal = signal
+
+		return nil
+	}
+}
+
+// WithRetryAfterStart - run readiness check after Start, Restart and Unpause
+// instead of `Retry.Operation` of the container options.
+//   - nil retryBackoff keeps the previous one.
+func WithRetryAfterStart(operation RetryOperation, retryBackoff backoff.BackOff) LifecycleOption {
+	return func(options *LifecycleOptions) (err error) {
+		if operation == nil {
+			return fmt.Errorf("%w: retry operation is nil", ErrInvalidOptions)
+		}
+
+		options.Retry.Operation = operation
+		if retryBackoff != nil {
+			options.Retry.Backoff = retryBackoff
+		}
+
+		return nil
+	}
+}
+
+// ApplyLifecycleOptions sets defaults and apply custom options.
+// Options aplies in order they passed.
+//
+// Each option rewrites previous value.
+func ApplyLifecycleOptions(customOpts ...LifecycleOption) (options LifecycleOptions, err error) {
+	options = options.getDefault()
+
+	for _, customOpt := range customOpts {
+		err = customOpt(&options)
+		if err != nil {
+			return LifecycleOptions{}, err
+		}
+	}
+
+	err = options.validate()
+	if err != nil {
+		return LifecycleOptions{}, fmt.Errorf("failed to options.validate: %w", err)
+	}
+
+	return options, nil
+}
+
+func (o LifecycleOptions) getDefault() (defaultOptions LifecycleOptions) {
+	retryBackoff := backoff.NewExponentialBackOff()
+	retryBackoff.MaxInterval = defaultRetryBackoffMaxInterval
+	retryBackoff.Reset()
+
+	return LifecycleOptions{
+		StopTimeout: defaultStopTimeout,
+		Signal:      defaultKillSignal,
+		Retry: RetryOptions{
+			Operation: nil,
+			Backoff:   retryBackoff,
+		},
+	}
+}
+
+func (o LifecycleOptions) validate() (err error) {
+	if o.StopTimeout < 0 {
+		return fmt.Errorf("%w: StopTimeout can't be negative", ErrInvalidOptions)
+	}
+	if o.Signal <= 0 {
+		return fmt.Errorf("%w: invalid Signal `%d`", ErrInvalidOptions, o.Signal)
+	}
+	if o.Retry.Operation != nil && o.Retry.Backoff == nil {
+		return fmt.Errorf("%w: Retry.Backoff is required", ErrInvalidOptions)
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package tcontainer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Lifecycle(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool, container, err := runBusybox(
+		context.Background(),
+		WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.AutoRemove = false }),
+	)
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(pool.Pool.Purge(container.Resource)) })
+
+	retryAfterStart := WithRetryAfterStart(pingBusyboxContainerServer, nil)
+
+	// stop
+	err = pool.Stop(context.Background(), container, WithStopTimeout(0))
+	require.NoError(err)
+	require.False(container.Container.State.Running)
+	require.Error(pingBusyboxContainerServer(context.Background(), container))
+	require.NoError(pool.Stop(context.Background(), container), "stop of stopped container")
+
+	// start
+	err = pool.Start(context.Background(), container, retryAfterStart)
+	require.NoError(err)
+	require.True(container.Container.State.Running)
+	require.NoError(pingBusyboxContainerServer(context.Background(), container))
+	require.NoError(pool.Start(context.Background(), container), "start of running container")
+
+	// restart
+	startedAt := container.Container.State.StartedAt
+	err = pool.Restart(context.Background(), container, WithStopTimeout(0), retryAfterStart)
+	require.NoError(err)
+	require.True(container.Container.State.Running)
+	require.True(container.Container.State.StartedAt.After(startedAt))
+
+	// pause
+	err = pool.Pause(context.Background(), container)
+	require.NoError(err)
+	require.True(container.Container.State.Paused)
+
+	// unpause
+	err = pool.Unpause(context.Background(), container, retryAfterStart)
+	require.NoError(err)
+	require.False(container.Container.State.Paused)
+
+	// kill
+	err = pool.Kill(context.Background(), container)
+	require.NoError(err)
+	require.False(container.Container.State.Running)
+	require.Equal(137, container.Container.State.ExitCode) //nolint:mnd // 128 + SIGKILL
+
+	// start after kill
+	err = pool.Start(context.Background(), container, retryAfterStart)
+	require.NoError(err)
+	require.True(container.Container.State.Running)
+}
+
+func Test_Lifecycle_AutoRemove(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	// init forwards the stop signal of the image (SIGTERM) to the shell
+	pool, container, err := runBusybox(
+		context.Background(),
+		WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.Init = true }),
+	)
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	// stop doesn't wait the timeout, container with AutoRemove is removed after stop
+	stopStartedAt := time.Now()
+	err = pool.Stop(context.Background(), container, WithStopTimeout(time.Minute))
+	require.NoError(err)
+	require.Less(time.Since(stopStartedAt), time.Second*5)
+	require.False(container.State().Running)
+
+	require.Eventually(func() bool {
+		_, err = container.Inspect(context.Background())
+		return errors.As(err, ptr((*docker.NoSuchContainer)(nil)))
+	}, time.Second*5, time.Millisecond*100)
+}
+
+func Test_Lifecycle_RetryFailed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool, container, err := runBusybox(context.Background())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	errNotReady := errors.New("not ready")
+	err = pool.Restart(
+		context.Background(), container,
+		WithStopTimeout(0),
+		WithRetryAfterStart(
+			func(context.Context, *Container) (err error) { return backoff.Permanent(errNotReady) },
+			nil,
+		),
+	)
+	require.ErrorIs(err, errNotReady)
+}
+
+func Test_ApplyLifecycleOptions(t *testing.T) {
+	t.Parallel()
+
+	retryOperation := func(context.Context, *Container) (err error) { return nil }
+	retryBackoff := backoff.NewConstantBackOff(time.Second)
+
+	testCases := []struct {
+		name  string
+		opts  []LifecycleOption
+		check func(require *require.Assertions, options LifecycleOptions)
+		err   error
+	}{
+		{
+			name: "default",
+			opts: nil,
+			check: func(require *require.Assertions, options LifecycleOptions) {
+				require.Equal(defaultStopTimeout, options.StopTimeout)
+				require.Equal(docker.SIGKILL, options.Signal)
+				require.Nil(options.Retry.Operation)
+				require.NotNil(options.Retry.Backoff)
+			},
+		},
+		{
+			name: "custom",
+			opts: []LifecycleOption{
+				WithStopTimeout(time.Second),
+				WithSignal(docker.SIGTERM),
+				WithRetryAfterStart(retryOperation, retryBackoff),
+			},
+			check: func(require *require.Assertions, options LifecycleOptions) {
+				require.Equal(time.Second, options.StopTimeout)
+				require.Equal(docker.SIGTERM, options.Signal)
+				require.NotNil(options.Retry.Operation)
+				require.Equal(retryBackoff, options.Retry.Backoff)
+			},
+		},
+		{
+			name: "WithRetryAfterStart/default_backoff",
+			opts: []LifecycleOption{WithRetryAfterStart(retryOperation, nil)},
+			check: func(require *require.Assertions, options LifecycleOptions) {
+				require.NotNil(options.Retry.Operation)
+				require.IsType(&backoff.ExponentialBackOff{}, options.Retry.Backoff)
+			},
+		},
+		{
+			name: "WithRetryAfterStart/nil_operation",
+			opts: []LifecycleOption{WithRetryAfterStart(nil, nil)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithStopTimeout/negative",
+			opts: []LifecycleOption{WithStopTimeout(-time.Second)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithSignal/invalid",
+			opts: []LifecycleOption{WithSignal(0)},
+			err:  ErrInvalidOptions,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			options, err := ApplyLifecycleOptions(tc.opts...)
+			if tc.err != nil {
+				require.ErrorIs(err, tc.err)
+				return
+			}
+			require.NoError(err)
+			tc.check(require, options)
+		})
+	}
+
+	// nil container
+	require.ErrorIs(t, Pool{}.Stop(context.Background(), nil), ErrInvalidOptions) //nolint:exhaustruct
+
+	// backoff of the container isn't shared
+	runBackoff := backoff.NewExponentialBackOff()
+	container := &Container{Resource: &dockertest.Resource{Container: &docker.Container{}}} //nolint:exhaustruct
+	container.options.Retry = RetryOptions{Operation: retryOperation, Backoff: runBackoff}
+	options, err := applyLifecycleOptions(container)
+	require.NoError(t, err)
+	require.NotSame(t, runBackoff, options.Retry.Backoff)
+	require.IsType(t, &backoff.ExponentialBackOff{}, options.Retry.Backoff)
+}
@@ -0,0 +1,120 @@
+package tcontainer
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// Keys of the attributes of the log records.
+const (
+	logKeyContainerName = "container_name"
+	logKeyContainerID   = "container_id"
+	logKeyImage         = "image"
+	logKeyError         = "error"
+)
+
+// WithLogger - returns derived Pool that writes structured events of its operations to the logger
+// (pull, create, start, reuse, readiness retries, expiry, prune, build). Nil logger disables logging.
+//   - Each action is logged with Info level, retry attempts and build output - with Debug level.
+//   - The parent Pool isn't changed.
+//
+// Example usage:
+//
+//	pool = pool.WithLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+func (p Pool) WithLogger(logger *slog.Logger) Pool {
+	p.logger = logger
+	return p
+}
+
+// log - returns logger of the pool, discards records if there is no logger.
+func (p Pool) log() *slog.Logger {
+	if p.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+
+	return p.logger
+}
+
+// containerLogAttrs - returns attributes of the container for log records, empty values are skipped.
+func containerLogAttrs(name, id, image string, attrs ...any) []any {
+	result := make([]any, 0, 6+len(attrs)) //nolint:mnd
+	if name != "" {
+		result = append(result, slog.String(logKeyContainerName, strings.TrimPrefix(name, "/")))
+	}
+	if id != "" {
+		result = append(result, slog.String(logKeyContainerID, id))
+	}
+	if image != "" {
+		result = append(result, slog.String(logKeyImage, image))
+	}
+
+	return slices.Clip(append(result, attrs...))
+}
+
+// containerImage - returns image of the container from its config.
+func containerImage(container *docker.Container) string {
+	if container.Config == nil {
+		return container.Image
+	}
+
+	return container.Config.Image
+}
+
+// logWriter - io.Writer that writes each line to the logger with Debug level (e.g. build output).
+type logWriter struct {
+	ctx    context.Context //nolint:containedctx // used only for log records of the call
+	logger *slog.Logger
+	msg    string
+	attrs  []any
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newLogWriter(ctx context.Context, logger *slog.Logger, msg string, attrs ...any) *logWriter {
+	return &logWriter{ctx: ctx, logger: logger, msg: msg, attrs: attrs, mu: sync.Mutex{}, buf: bytes.Buffer{}}
+}
+
+func (w *logWriter) Write(data []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(data)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// incomplete line - wait for the rest
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+
+		w.logLine(line)
+	}
+
+	return len(data), nil
+}
+
+// Flush - writes the last incomplete line.
+func (w *logWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.logLine(w.buf.String())
+	w.buf.Reset()
+}
+
+func (w *logWriter) logLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	w.logger.DebugContext(w.ctx, w.msg, append(slices.Clip(w.attrs), slog.String("line", line))...)
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	context "context"
+
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BeforeCreateHook is an autogenerated mock type for the BeforeCreateHook type
+type BeforeCreateHook struct {
+	mock.Mock
+}
+
+type BeforeCreateHook_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BeforeCreateHook) EXPECT() *BeforeCreateHook_Expecter {
+	return &BeforeCreateHook_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx, options
+func (_m *BeforeCreateHook) Execute(ctx context.Context, options *tcontainer.RunOptions) error {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *tcontainer.RunOptions) error); ok {
+		r0 = rf(ctx, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BeforeCreateHook_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type BeforeCreateHook_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - options *tcontainer.RunOptions
+func (_e *BeforeCreateHook_Expecter) Execute(ctx interface{}, options interface{}) *BeforeCreateHook_Execute_Call {
+	return &BeforeCreateHook_Execute_Call{Call: _e.mock.On("Execute", ctx, options)}
+}
+
+func (_c *BeforeCreateHook_Execute_Call) Run(run func(ctx context.Context, options *tcontainer.RunOptions)) *BeforeCreateHook_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*tcontainer.RunOptions))
+	})
+	return _c
+}
+
+func (_c *BeforeCreateHook_Execute_Call) Return(err error) *BeforeCreateHook_Execute_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *BeforeCreateHook_Execute_Call) RunAndReturn(run func(context.Context, *tcontainer.RunOptions) error) *BeforeCreateHook_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBeforeCreateHook creates a new instance of BeforeCreateHook. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBeforeCreateHook(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BeforeCreateHook {
+	mock := &BeforeCreateHook{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
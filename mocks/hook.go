@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	context "context"
+
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Hook is an autogenerated mock type for the Hook type
+type Hook struct {
+	mock.Mock
+}
+
+type Hook_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Hook) EXPECT() *Hook_Expecter {
+	return &Hook_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx, container
+func (_m *Hook) Execute(ctx context.Context, container *tcontainer.Container) error {
+	ret := _m.Called(ctx, container)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *tcontainer.Container) error); ok {
+		r0 = rf(ctx, container)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Hook_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type Hook_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - container *tcontainer.Container
+func (_e *Hook_Expecter) Execute(ctx interface{}, container interface{}) *Hook_Execute_Call {
+	return &Hook_Execute_Call{Call: _e.mock.On("Execute", ctx, container)}
+}
+
+func (_c *Hook_Execute_Call) Run(run func(ctx context.Context, container *tcontainer.Container)) *Hook_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*tcontainer.Container))
+	})
+	return _c
+}
+
+func (_c *Hook_Execute_Call) Return(err error) *Hook_Execute_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Hook_Execute_Call) RunAndReturn(run func(context.Context, *tcontainer.Container) error) *Hook_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewHook creates a new instance of Hook. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHook(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Hook {
+	mock := &Hook{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,135 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	context "context"
+
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Instrumentation is an autogenerated mock type for the Instrumentation type
+type Instrumentation struct {
+	mock.Mock
+}
+
+type Instrumentation_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Instrumentation) EXPECT() *Instrumentation_Expecter {
+	return &Instrumentation_Expecter{mock: &_m.Mock}
+}
+
+// RetryAttempt provides a mock function with given fields: ctx, info, attempt, err
+func (_m *Instrumentation) RetryAttempt(ctx context.Context, info tcontainer.OperationInfo, attempt int, err error) {
+	_m.Called(ctx, info, attempt, err)
+}
+
+// Instrumentation_RetryAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetryAttempt'
+type Instrumentation_RetryAttempt_Call struct {
+	*mock.Call
+}
+
+// RetryAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - info tcontainer.OperationInfo
+//   - attempt int
+//   - err error
+func (_e *Instrumentation_Expecter) RetryAttempt(ctx interface{}, info interface{}, attempt interface{}, err interface{}) *Instrumentation_RetryAttempt_Call {
+	return &Instrumentation_RetryAttempt_Call{Call: _e.mock.On("RetryAttempt", ctx, info, attempt, err)}
+}
+
+func (_c *Instrumentation_RetryAttempt_Call) Run(run func(ctx context.Context, info tcontainer.OperationInfo, attempt int, err error)) *Instrumentation_RetryAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tcontainer.OperationInfo), args[2].(int), args[3].(error))
+	})
+	return _c
+}
+
+func (_c *Instrumentation_RetryAttempt_Call) Return() *Instrumentation_RetryAttempt_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Instrumentation_RetryAttempt_Call) RunAndReturn(run func(context.Context, tcontainer.OperationInfo, int, error)) *Instrumentation_RetryAttempt_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Start provides a mock function with given fields: ctx, operation, info
+func (_m *Instrumentation) Start(ctx context.Context, operation tcontainer.Operation, info tcontainer.OperationInfo) (context.Context, tcontainer.OperationEnd) {
+	ret := _m.Called(ctx, operation, info)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 context.Context
+	var r1 tcontainer.OperationEnd
+	if rf, ok := ret.Get(0).(func(context.Context, tcontainer.Operation, tcontainer.OperationInfo) (context.Context, tcontainer.OperationEnd)); ok {
+		return rf(ctx, operation, info)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, tcontainer.Operation, tcontainer.OperationInfo) context.Context); ok {
+		r0 = rf(ctx, operation, info)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, tcontainer.Operation, tcontainer.OperationInfo) tcontainer.OperationEnd); ok {
+		r1 = rf(ctx, operation, info)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(tcontainer.OperationEnd)
+		}
+	}
+
+	return r0, r1
+}
+
+// Instrumentation_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
+type Instrumentation_Start_Call struct {
+	*mock.Call
+}
+
+// Start is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operation tcontainer.Operation
+//   - info tcontainer.OperationInfo
+func (_e *Instrumentation_Expecter) Start(ctx interface{}, operation interface{}, info interface{}) *Instrumentation_Start_Call {
+	return &Instrumentation_Start_Call{Call: _e.mock.On("Start", ctx, operation, info)}
+}
+
+func (_c *Instrumentation_Start_Call) Run(run func(ctx context.Context, operation tcontainer.Operation, info tcontainer.OperationInfo)) *Instrumentation_Start_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tcontainer.Operation), args[2].(tcontainer.OperationInfo))
+	})
+	return _c
+}
+
+func (_c *Instrumentation_Start_Call) Return(opCtx context.Context, end tcontainer.OperationEnd) *Instrumentation_Start_Call {
+	_c.Call.Return(opCtx, end)
+	return _c
+}
+
+func (_c *Instrumentation_Start_Call) RunAndReturn(run func(context.Context, tcontainer.Operation, tcontainer.OperationInfo) (context.Context, tcontainer.OperationEnd)) *Instrumentation_Start_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewInstrumentation creates a new instance of Instrumentation. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInstrumentation(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Instrumentation {
+	mock := &Instrumentation{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
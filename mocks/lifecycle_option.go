@@ -0,0 +1,81 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LifecycleOption is an autogenerated mock type for the LifecycleOption type
+type LifecycleOption struct {
+	mock.Mock
+}
+
+type LifecycleOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LifecycleOption) EXPECT() *LifecycleOption_Expecter {
+	return &LifecycleOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *LifecycleOption) Execute(options *tcontainer.LifecycleOptions) error {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*tcontainer.LifecycleOptions) error); ok {
+		r0 = rf(options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LifecycleOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type LifecycleOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *tcontainer.LifecycleOptions
+func (_e *LifecycleOption_Expecter) Execute(options interface{}) *LifecycleOption_Execute_Call {
+	return &LifecycleOption_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *LifecycleOption_Execute_Call) Run(run func(options *tcontainer.LifecycleOptions)) *LifecycleOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*tcontainer.LifecycleOptions))
+	})
+	return _c
+}
+
+func (_c *LifecycleOption_Execute_Call) Return(err error) *LifecycleOption_Execute_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *LifecycleOption_Execute_Call) RunAndReturn(run func(*tcontainer.LifecycleOptions) error) *LifecycleOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLifecycleOption creates a new instance of LifecycleOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLifecycleOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LifecycleOption {
+	mock := &LifecycleOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
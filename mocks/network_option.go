@@ -0,0 +1,81 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NetworkOption is an autogenerated mock type for the NetworkOption type
+type NetworkOption struct {
+	mock.Mock
+}
+
+type NetworkOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NetworkOption) EXPECT() *NetworkOption_Expecter {
+	return &NetworkOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *NetworkOption) Execute(options *tcontainer.NetworkOptions) error {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*tcontainer.NetworkOptions) error); ok {
+		r0 = rf(options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NetworkOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type NetworkOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *tcontainer.NetworkOptions
+func (_e *NetworkOption_Expecter) Execute(options interface{}) *NetworkOption_Execute_Call {
+	return &NetworkOption_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *NetworkOption_Execute_Call) Run(run func(options *tcontainer.NetworkOptions)) *NetworkOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*tcontainer.NetworkOptions))
+	})
+	return _c
+}
+
+func (_c *NetworkOption_Execute_Call) Return(err error) *NetworkOption_Execute_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *NetworkOption_Execute_Call) RunAndReturn(run func(*tcontainer.NetworkOptions) error) *NetworkOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewNetworkOption creates a new instance of NetworkOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNetworkOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NetworkOption {
+	mock := &NetworkOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
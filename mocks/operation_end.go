@@ -0,0 +1,68 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OperationEnd is an autogenerated mock type for the OperationEnd type
+type OperationEnd struct {
+	mock.Mock
+}
+
+type OperationEnd_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OperationEnd) EXPECT() *OperationEnd_Expecter {
+	return &OperationEnd_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: result
+func (_m *OperationEnd) Execute(result tcontainer.OperationResult) {
+	_m.Called(result)
+}
+
+// OperationEnd_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type OperationEnd_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - result tcontainer.OperationResult
+func (_e *OperationEnd_Expecter) Execute(result interface{}) *OperationEnd_Execute_Call {
+	return &OperationEnd_Execute_Call{Call: _e.mock.On("Execute", result)}
+}
+
+func (_c *OperationEnd_Execute_Call) Run(run func(result tcontainer.OperationResult)) *OperationEnd_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(tcontainer.OperationResult))
+	})
+	return _c
+}
+
+func (_c *OperationEnd_Execute_Call) Return() *OperationEnd_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *OperationEnd_Execute_Call) RunAndReturn(run func(tcontainer.OperationResult)) *OperationEnd_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewOperationEnd creates a new instance of OperationEnd. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOperationEnd(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OperationEnd {
+	mock := &OperationEnd{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
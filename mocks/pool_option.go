@@ -0,0 +1,81 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PoolOption is an autogenerated mock type for the PoolOption type
+type PoolOption struct {
+	mock.Mock
+}
+
+type PoolOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PoolOption) EXPECT() *PoolOption_Expecter {
+	return &PoolOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *PoolOption) Execute(options *tcontainer.PoolOptions) error {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*tcontainer.PoolOptions) error); ok {
+		r0 = rf(options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PoolOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type PoolOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *tcontainer.PoolOptions
+func (_e *PoolOption_Expecter) Execute(options interface{}) *PoolOption_Execute_Call {
+	return &PoolOption_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *PoolOption_Execute_Call) Run(run func(options *tcontainer.PoolOptions)) *PoolOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*tcontainer.PoolOptions))
+	})
+	return _c
+}
+
+func (_c *PoolOption_Execute_Call) Return(err error) *PoolOption_Execute_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *PoolOption_Execute_Call) RunAndReturn(run func(*tcontainer.PoolOptions) error) *PoolOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPoolOption creates a new instance of PoolOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPoolOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PoolOption {
+	mock := &PoolOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,7 +5,7 @@ package tcontainer_mocks
 import (
 	context "context"
 
-	dockertest "github.com/ory/dockertest/v3"
+	tcontainer "github.com/kiteggrad/tcontainer"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -23,7 +23,7 @@ func (_m *RetryOperation) EXPECT() *RetryOperation_Expecter {
 }
 
 // Execute provides a mock function with given fields: ctx, container
-func (_m *RetryOperation) Execute(ctx context.Context, container *dockertest.Resource) error {
+func (_m *RetryOperation) Execute(ctx context.Context, container *tcontainer.Container) error {
 	ret := _m.Called(ctx, container)
 
 	if len(ret) == 0 {
@@ -31,7 +31,7 @@ func (_m *RetryOperation) Execute(ctx context.Context, container *dockertest.Res
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *dockertest.Resource) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *tcontainer.Container) error); ok {
 		r0 = rf(ctx, container)
 	} else {
 		r0 = ret.Error(0)
@@ -47,14 +47,14 @@ type RetryOperation_Execute_Call struct {
 
 // Execute is a helper method to define mock.On call
 //   - ctx context.Context
-//   - container *dockertest.Resource
+//   - container *tcontainer.Container
 func (_e *RetryOperation_Expecter) Execute(ctx interface{}, container interface{}) *RetryOperation_Execute_Call {
 	return &RetryOperation_Execute_Call{Call: _e.mock.On("Execute", ctx, container)}
 }
 
-func (_c *RetryOperation_Execute_Call) Run(run func(ctx context.Context, container *dockertest.Resource)) *RetryOperation_Execute_Call {
+func (_c *RetryOperation_Execute_Call) Run(run func(ctx context.Context, container *tcontainer.Container)) *RetryOperation_Execute_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*dockertest.Resource))
+		run(args[0].(context.Context), args[1].(*tcontainer.Container))
 	})
 	return _c
 }
@@ -64,7 +64,7 @@ func (_c *RetryOperation_Execute_Call) Return(err error) *RetryOperation_Execute
 	return _c
 }
 
-func (_c *RetryOperation_Execute_Call) RunAndReturn(run func(context.Context, *dockertest.Resource) error) *RetryOperation_Execute_Call {
+func (_c *RetryOperation_Execute_Call) RunAndReturn(run func(context.Context, *tcontainer.Container) error) *RetryOperation_Execute_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,81 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import (
+	tcontainer "github.com/kiteggrad/tcontainer"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SnapshotOption is an autogenerated mock type for the SnapshotOption type
+type SnapshotOption struct {
+	mock.Mock
+}
+
+type SnapshotOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SnapshotOption) EXPECT() *SnapshotOption_Expecter {
+	return &SnapshotOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *SnapshotOption) Execute(options *tcontainer.SnapshotOptions) error {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*tcontainer.SnapshotOptions) error); ok {
+		r0 = rf(options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SnapshotOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type SnapshotOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *tcontainer.SnapshotOptions
+func (_e *SnapshotOption_Expecter) Execute(options interface{}) *SnapshotOption_Execute_Call {
+	return &SnapshotOption_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *SnapshotOption_Execute_Call) Run(run func(options *tcontainer.SnapshotOptions)) *SnapshotOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*tcontainer.SnapshotOptions))
+	})
+	return _c
+}
+
+func (_c *SnapshotOption_Execute_Call) Return(err error) *SnapshotOption_Execute_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *SnapshotOption_Execute_Call) RunAndReturn(run func(*tcontainer.SnapshotOptions) error) *SnapshotOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSnapshotOption creates a new instance of SnapshotOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSnapshotOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SnapshotOption {
+	mock := &SnapshotOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,148 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package tcontainer_mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// TestingT is an autogenerated mock type for the TestingT type
+type TestingT struct {
+	mock.Mock
+}
+
+type TestingT_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TestingT) EXPECT() *TestingT_Expecter {
+	return &TestingT_Expecter{mock: &_m.Mock}
+}
+
+// Fatal provides a mock function with given fields: args
+func (_m *TestingT) Fatal(args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// TestingT_Fatal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fatal'
+type TestingT_Fatal_Call struct {
+	*mock.Call
+}
+
+// Fatal is a helper method to define mock.On call
+//   - args ...interface{}
+func (_e *TestingT_Expecter) Fatal(args ...interface{}) *TestingT_Fatal_Call {
+	return &TestingT_Fatal_Call{Call: _e.mock.On("Fatal",
+		append([]interface{}{}, args...)...)}
+}
+
+func (_c *TestingT_Fatal_Call) Run(run func(args ...interface{})) *TestingT_Fatal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TestingT_Fatal_Call) Return() *TestingT_Fatal_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TestingT_Fatal_Call) RunAndReturn(run func(...interface{})) *TestingT_Fatal_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Helper provides a mock function with no fields
+func (_m *TestingT) Helper() {
+	_m.Called()
+}
+
+// TestingT_Helper_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Helper'
+type TestingT_Helper_Call struct {
+	*mock.Call
+}
+
+// Helper is a helper method to define mock.On call
+func (_e *TestingT_Expecter) Helper() *TestingT_Helper_Call {
+	return &TestingT_Helper_Call{Call: _e.mock.On("Helper")}
+}
+
+func (_c *TestingT_Helper_Call) Run(run func()) *TestingT_Helper_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *TestingT_Helper_Call) Return() *TestingT_Helper_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TestingT_Helper_Call) RunAndReturn(run func()) *TestingT_Helper_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Skip provides a mock function with given fields: args
+func (_m *TestingT) Skip(args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
+// TestingT_Skip_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Skip'
+type TestingT_Skip_Call struct {
+	*mock.Call
+}
+
+// Skip is a helper method to define mock.On call
+//   - args ...interface{}
+func (_e *TestingT_Expecter) Skip(args ...interface{}) *TestingT_Skip_Call {
+	return &TestingT_Skip_Call{Call: _e.mock.On("Skip",
+		append([]interface{}{}, args...)...)}
+}
+
+func (_c *TestingT_Skip_Call) Run(run func(args ...interface{})) *TestingT_Skip_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TestingT_Skip_Call) Return() *TestingT_Skip_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TestingT_Skip_Call) RunAndReturn(run func(...interface{})) *TestingT_Skip_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewTestingT creates a new instance of TestingT. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTestingT(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TestingT {
+	mock := &TestingT{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,243 @@
+package tcontainer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// ErrNetworkNotFound - occurs when network with the name doesn't exist.
+var ErrNetworkNotFound = errors.New("network not found")
+
+// CreateNetwork - creates new docker network labelled with DefaultLabelKeyValue.
+//   - Use WithNetwork(network, aliases...) to run containers in the network.
+//   - Use network.Close() to remove the network (containers will be disconnected).
+//   - Use WithNetworkReuse() to reuse existing network with the same name.
+func (p Pool) CreateNetwork(ctx context.Context, customOpts ...NetworkOption) (network *dockertest.Network, err error) {
+	options, err := ApplyNetworkOptions(uuid.NewString(), customOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to applyNetworkOptions: %w", err)
+	}
+
+	if options.Reuse {
+		network, err = p.NetworkByName(ctx, options.Name)
+		switch {
+		case err == nil:
+			return network, nil
+		case !errors.Is(err, ErrNetworkNotFound):
+			return nil, fmt.Errorf("failed to NetworkByName: %w", err)
+		}
+	}
+
+	network, err = p.Pool.CreateNetwork(options.Name, func(config *docker.CreateNetworkOptions) {
+		*config = options.toCreateNetworkOptions(ctx)
+	})
+	if err != nil {
+		// network could be created concurrently
+		var dockerErr *docker.Error
+		if options.Reuse && errors.As(err, &dockerErr) && dockerErr.Status == http.StatusConflict {
+			network, err = p.NetworkByName(ctx, options.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to NetworkByName after create conflict: %w", err)
+			}
+
+			return network, nil
+		}
+
+		return nil, fmt.Errorf("failed to CreateNetwork: %w", err)
+	}
+
+	// refresh network info (e.g. labels)
+	network.Network, err = p.networkInfo(ctx, network.Network.ID)
+	if err != nil {
+		_ = network.Close()
+		return nil, fmt.Errorf("failed to NetworkInfo: %w", err)
+	}
+
+	return network, nil
+}
+
+// NetworkByName - returns existing network by exact name or error that wraps ErrNetworkNotFound.
+func (p Pool) NetworkByName(ctx context.Context, name string) (network *dockertest.Network, err error) {
+	// only dockertest binds the network to the pool (see (*dockertest.Network).Close), it has no ctx
+	networks, err := callWithContext(ctx, func() ([]dockertest.Network, error) { return p.Pool.NetworksByName(name) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to NetworksByName: %w", err)
+	}
+
+	switch len(networks) {
+	case 0:
+		return nil, fmt.Errorf("%w: `%s`", ErrNetworkNotFound, name)
+	case 1:
+	default:
+		return nil, fmt.Errorf("found more than 1 network with name `%s`", name)
+	}
+
+	network = &networks[0]
+
+	// network list doesn't contain connected containers
+	network.Network, err = p.networkInfo(ctx, network.Network.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to NetworkInfo: %w", err)
+	}
+
+	return network, nil
+}
+
+// Connect - connects running container to the network with optional DNS aliases.
+//   - container.Container is refreshed, so GetAPIEndpoints returns actual addresses after reconnect.
+func (p Pool) Connect(
+	ctx context.Context, container *Container, network *dockertest.Network, aliases ...string,
+) (err error) {
+	if container == nil || network == nil {
+		return fmt.Errorf("%w: container and network are required", ErrInvalidOptions)
+	}
+
+	err = p.connect(ctx, container.Resource, network.Network.ID, aliases)
+	if err != nil {
+		return err
+	}
+
+	network.Network, err = p.networkInfo(ctx, network.Network.ID)
+	if err != nil {
+		return fmt.Errorf("failed to NetworkInfo: %w", err)
+	}
+
+	return nil
+}
+
+// Disconnect - disconnects running container from the network.
+//   - container.Container is refreshed, so GetAPIEndpoints returns actual addresses.
+func (p Pool) Disconnect(ctx context.Context, container *Container, network *dockertest.Network) (err error) {
+	if container == nil || network == nil {
+		return fmt.Errorf("%w: container and network are required", ErrInvalidOptions)
+	}
+
+	err = p.disconnect(ctx, container.Resource, network.Network.ID)
+	if err != nil {
+		return err
+	}
+
+	network.Network, err = p.networkInfo(ctx, network.Network.ID)
+	if err != nil {
+		return fmt.Errorf("failed to NetworkInfo: %w", err)
+	}
+
+	return nil
+}
+
+// Partition - isolates containers a and b from each other by disconnecting b from all networks shared with a.
+// Returns heal function that connects b back to the networks with the same aliases.
+//   - Disconnecting b from the "bridge" network also makes its host ports unavailable until heal.
+//   - Containers are refreshed, so GetAPIEndpoints returns actual addresses after heal.
+func (p Pool) Partition(
+	ctx context.Context, a, b *Container,
+) (heal func(ctx context.Context) (err error), err error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("%w: both containers are required", ErrInvalidOptions)
+	}
+
+	err = p.refreshContainer(ctx, a.Resource)
+	if err != nil {
+		return nil, err
+	}
+	err = p.refreshContainer(ctx, b.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasesByNetworkID := make(map[string][]string)
+	for name, bEndpoint := range b.Container.NetworkSettings.Networks {
+		if _, ok := a.Container.NetworkSettings.Networks[name]; !ok {
+			continue
+		}
+
+		aliasesByNetworkID[bEndpoint.NetworkID] = userAliases(b.Container.ID, bEndpoint.Aliases)
+	}
+
+	if len(aliasesByNetworkID) == 0 {
+		return nil, fmt.Errorf("%w: containers have no shared networks", ErrInvalidOptions)
+	}
+
+	disconnectedNetworkIDs := make([]string, 0, len(aliasesByNetworkID))
+	heal = func(ctx context.Context) (err error) {
+		for _, networkID := range disconnectedNetworkIDs {
+			err = p.connect(ctx, b.Resource, networkID, aliasesByNetworkID[networkID])
+			if err != nil {
+				return err
+			}
+		}
+		disconnectedNetworkIDs = disconnectedNetworkIDs[:0]
+
+		return p.refreshContainer(ctx, a.Resource)
+	}
+
+	for _, networkID := range slices.Sorted(maps.Keys(aliasesByNetworkID)) {
+		err = p.disconnect(ctx, b.Resource, networkID)
+		if err != nil {
+			return nil, errors.Join(err, heal(ctx))
+		}
+		disconnectedNetworkIDs = append(disconnectedNetworkIDs, networkID)
+	}
+
+	return heal, p.refreshContainer(ctx, a.Resource)
+}
+
+func (p Pool) connect(
+	ctx context.Context, container *dockertest.Resource, networkID string, aliases []string,
+) (err error) {
+	err = p.Pool.Client.ConnectNetwork(networkID, docker.NetworkConnectionOptions{
+		Container:      container.Container.ID,
+		EndpointConfig: &docker.EndpointConfig{Aliases: aliases}, //nolint:exhaustruct
+		Force:          false,
+		Context:        ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ConnectNetwork: %w", err)
+	}
+
+	return p.refreshContainer(ctx, container)
+}
+
+func (p Pool) disconnect(ctx context.Context, container *dockertest.Resource, networkID string) (err error) {
+	err = p.Pool.Client.DisconnectNetwork(networkID, docker.NetworkConnectionOptions{
+		Container:      container.Container.ID,
+		EndpointConfig: nil,
+		Force:          false,
+		Context:        ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to DisconnectNetwork: %w", err)
+	}
+
+	return p.refreshContainer(ctx, container)
+}
+
+// networkInfo - returns actual network state (e.g. connected containers).
+func (p Pool) networkInfo(ctx context.Context, networkID string) (network *docker.Network, err error) {
+	network = &docker.Network{} //nolint:exhaustruct
+	err = dockerRequest(ctx, p.Pool.Client, http.MethodGet, "", "/networks/"+networkID, nil, network)
+	if isDockerErrorStatus(err, http.StatusNotFound) {
+		return nil, &docker.NoSuchNetwork{ID: networkID}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return network, nil
+}
+
+// userAliases - returns aliases without ones that docker adds automatically (short container id).
+func userAliases(containerID string, aliases []string) []string {
+	const shortIDLength = 12
+
+	return slices.DeleteFunc(slices.Clone(aliases), func(alias string) bool {
+		return alias == containerID || (len(containerID) >= shortIDLength && alias == containerID[:shortIDLength])
+	})
+}
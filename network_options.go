@@ -0,0 +1,146 @@
+package tcontainer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+type (
+	// NetworkOptions for (Pool).CreateNetwork function.
+	NetworkOptions struct {
+		Name       string
+		Driver     string
+		Internal   bool
+		EnableIPv6 bool
+		Labels     map[string]string
+		Options    map[string]any
+
+		// Reuse existing network with the same name instead of getting an error that the network already exists.
+		// Useful together with (RunOptions).Reuse - reused container stays on the same network.
+		//
+		// Default: `false`
+		Reuse bool
+	}
+
+	// NetworkOption - option for (Pool).CreateNetwork function.
+	// See [ApplyNetworkOptions].
+	NetworkOption func(options *NetworkOptions) (err error)
+)
+
+// WithNetworkName - use custom network name instead of random.
+// Name is formatted the same way as in [WithContainerName].
+//
+// Example usage:
+//
+//	WithNetworkName(t.Name(), "backend") // "Test/with/invalid/chars", "backend" -> "Test-with-invalid-chars-backend"
+func WithNetworkName(nameParts ...string) NetworkOption {
+	return func(options *NetworkOptions) (err error) {
+		options.Name = formatContainerName(nameParts...)
+		return nil
+	}
+}
+
+// WithNetworkReuse - reuse existing network with the same name. See [NetworkOptions].
+func WithNetworkReuse() NetworkOption {
+	return func(options *NetworkOptions) (err error) {
+		options.Reuse = true
+		return nil
+	}
+}
+
+// WithNetworkDriver - use custom network driver (e.g. "bridge", "overlay").
+// Empty driver means docker default.
+func WithNetworkDriver(driver string) NetworkOption {
+	return func(options *NetworkOptions) (err error) {
+		options.Driver = driver
+		return nil
+	}
+}
+
+// WithInternal - create network without access to external networks.
+func WithInternal() NetworkOption {
+	return func(options *NetworkOptions) (err error) {
+		options.Internal = true
+		return nil
+	}
+}
+
+// WithNetworkLabels - adds labels to the network.
+// Rewrites labels with the same keys, other labels (like DefaultLabelKeyValue) are kept.
+func WithNetworkLabels(labels map[string]string) NetworkOption {
+	return func(options *NetworkOptions) (err error) {
+		if _, ok := labels[""]; ok {
+			return fmt.Errorf("%w: label key is required", ErrInvalidOptions)
+		}
+
+		if options.Labels == nil {
+			options.Labels = make(map[string]string, len(labels))
+		}
+		maps.Copy(options.Labels, labels)
+
+		return nil
+	}
+}
+
+// ApplyNetworkOptions sets defaults and apply custom options.
+// Options aplies in order they passed.
+//
+// Each option rewrites previous value
+//
+//	ApplyNetworkOptions(uuid, WithNetworkName("first"), WithNetworkName("second")) // "second"
+func ApplyNetworkOptions(uuid string, customOpts ...NetworkOption) (
+	options NetworkOptions, err error,
+) {
+	options = options.getDefault(uuid)
+
+	for _, customOpt := range customOpts {
+		err = customOpt(&options)
+		if err != nil {
+			return NetworkOptions{}, err
+		}
+	}
+
+	err = options.validate()
+	if err != nil {
+		return NetworkOptions{}, fmt.Errorf("failed to options.validate: %w", err)
+	}
+
+	return options, nil
+}
+
+func (o NetworkOptions) getDefault(uuid string) (defaultNetworkOptions NetworkOptions) {
+	return NetworkOptions{
+		Name:       DefaultLabelKeyValue + "-" + uuid,
+		Driver:     "",
+		Internal:   false,
+		EnableIPv6: false,
+		Labels:     map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue},
+		Options:    nil,
+		Reuse:      false,
+	}
+}
+
+func (o NetworkOptions) validate() (err error) {
+	if !containerNameRegexp.MatchString(o.Name) {
+		return fmt.Errorf("%w: invalid network name `%s`", ErrInvalidOptions, o.Name)
+	}
+
+	return nil
+}
+
+func (o NetworkOptions) toCreateNetworkOptions(ctx context.Context) (createNetworkOptions docker.CreateNetworkOptions) {
+	return docker.CreateNetworkOptions{
+		Name:           o.Name,
+		Driver:         o.Driver,
+		IPAM:           nil,
+		Options:        o.Options,
+		Labels:         o.Labels,
+		CheckDuplicate: true,
+		Internal:       o.Internal,
+		EnableIPv6:     o.EnableIPv6,
+		Context:        ctx,
+	}
+}
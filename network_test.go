@@ -0,0 +1,239 @@
+package tcontainer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateNetwork(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	pool := MustNewPool("")
+
+	network, err := pool.CreateNetwork(context.Background())
+	require.NoError(err)
+
+	require.Equal(DefaultLabelKeyValue, network.Network.Labels[DefaultLabelKeyValue])
+
+	require.NoError(network.Close())
+	_, err = pool.NetworkByName(context.Background(), network.Network.Name)
+	require.ErrorIs(err, ErrNetworkNotFound)
+}
+
+func Test_CreateNetwork_Reuse(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := MustNewPool("")
+
+	network, err := pool.CreateNetwork(context.Background(), WithNetworkName(t.Name()))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(network.Close()) })
+
+	// without reuse
+	_, err = pool.CreateNetwork(context.Background(), WithNetworkName(t.Name()))
+	require.Error(err)
+
+	// with reuse
+	reusedNetwork, err := pool.CreateNetwork(context.Background(), WithNetworkName(t.Name()), WithNetworkReuse())
+	require.NoError(err)
+	require.Equal(network.Network.ID, reusedNetwork.Network.ID)
+}
+
+func Test_RunOptions_WithNetwork(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := MustNewPool("")
+
+	network, err := pool.CreateNetwork(context.Background(), WithNetworkName(t.Name()))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(network.Close()) })
+
+	// run server with alias
+	serverOpts := []RunOption{WithContainerName(t.Name(), "server"), WithNetwork(network, "server")}
+	_, server, err := runBusybox(context.Background(), serverOpts...)
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(server.Close()) })
+	require.NotEmpty(server.GetIPInNetwork(network))
+
+	// request server by alias
+	_, client, err := runBusybox(context.Background(), WithNetwork(network))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(client.Close()) })
+
+	result, err := client.Exec(context.Background(), "wget", "-q", "-O", "-", "http://server:"+containerAPIPort)
+	require.NoError(err)
+	require.Zero(result.ExitCode, result.Stderr)
+
+	// reused container stays on the network
+	reusedNetwork, err := pool.CreateNetwork(context.Background(), WithNetworkName(t.Name()), WithNetworkReuse())
+	require.NoError(err)
+	_, reusedServer, err := runBusybox(context.Background(), WithContainerName(t.Name(), "server"), WithNetwork(reusedNetwork, "server"), WithReuse(false))
+	require.NoError(err)
+	require.Equal(server.Container.ID, reusedServer.Container.ID)
+
+	// can't reuse container without alias
+	_, _, err = runBusybox(context.Background(), WithContainerName(t.Name(), "server"), WithNetwork(reusedNetwork, "other"), WithReuse(false))
+	require.ErrorIs(err, ErrReuseContainerConflict)
+}
+
+func Test_RunOptions_WithNetworkMode(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	_, server, err := runBusybox(context.Background())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(server.Close()) })
+
+	// share network stack with the server
+	pool, client, err := runBusybox(context.Background(), WithNetworkMode("container:"+server.Container.ID), func(options *RunOptions) (err error) {
+		options.Cmd = []string{"tail", "-f", "/dev/null"}
+		options.ExposedPorts = nil
+		options.Retry.Operation = nil
+		return nil
+	})
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(client.Close()) })
+
+	result, err := client.Exec(context.Background(), "wget", "-q", "-O", "-", "http://localhost:"+containerAPIPort)
+	require.NoError(err)
+	require.Zero(result.ExitCode, result.Stderr)
+
+	// network mode conflicts with networks
+	network, err := pool.CreateNetwork(context.Background())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(network.Close()) })
+	_, err = ApplyRunOptions("busybox", WithNetworkMode("host"), WithNetwork(network))
+	require.ErrorIs(err, ErrOptionConflict)
+}
+
+func Test_NetworkOptions(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	options, err := ApplyNetworkOptions("uuid")
+	require.NoError(err)
+	require.Equal("tcontainer-uuid", options.Name)
+	require.Equal(map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue}, options.Labels)
+	require.False(options.Reuse)
+
+	options, err = ApplyNetworkOptions("uuid", WithNetworkName("Test/name", "", "backend"), WithNetworkReuse())
+	require.NoError(err)
+	require.Equal("Test-name-backend", options.Name)
+	require.True(options.Reuse)
+
+	options, err = ApplyNetworkOptions("uuid",
+		WithNetworkDriver("bridge"), WithInternal(), WithNetworkLabels(map[string]string{"app": "test"}),
+	)
+	require.NoError(err)
+	require.Equal("bridge", options.Driver)
+	require.True(options.Internal)
+	require.Equal(map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue, "app": "test"}, options.Labels)
+
+	_, err = ApplyNetworkOptions("uuid", WithNetworkName(""))
+	require.ErrorIs(err, ErrInvalidOptions)
+
+	_, err = ApplyNetworkOptions("uuid", WithNetworkLabels(map[string]string{"": "value"}))
+	require.ErrorIs(err, ErrInvalidOptions)
+}
+
+func Test_Connect_Disconnect(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool, container, err := runBusybox(context.Background())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	network, err := pool.CreateNetwork(context.Background())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(network.Close()) })
+
+	bridgeIP := GetAPIEndpoints(container.Resource)[containerAPIPort].IP
+	require.NotEmpty(bridgeIP)
+
+	// connect
+	err = pool.Connect(context.Background(), container, network, "alias")
+	require.NoError(err)
+	require.Contains(container.Container.NetworkSettings.Networks[network.Network.Name].Aliases, "alias")
+	require.Contains(network.Network.Containers, container.Container.ID)
+	networkIP := container.GetIPInNetwork(network)
+	require.NotEmpty(networkIP)
+
+	// disconnect from bridge - endpoints are refreshed
+	bridgeNetwork, err := pool.NetworkByName(context.Background(), "bridge")
+	require.NoError(err)
+	err = pool.Disconnect(context.Background(), container, bridgeNetwork)
+	require.NoError(err)
+	require.Equal(networkIP, GetAPIEndpoints(container.Resource)[containerAPIPort].IP)
+
+	// disconnect
+	err = pool.Disconnect(context.Background(), container, network)
+	require.NoError(err)
+	require.NotContains(container.Container.NetworkSettings.Networks, network.Network.Name)
+	require.NotContains(network.Network.Containers, container.Container.ID)
+
+	// invalid args
+	require.ErrorIs(pool.Connect(context.Background(), nil, network), ErrInvalidOptions)
+	require.ErrorIs(pool.Disconnect(context.Background(), container, nil), ErrInvalidOptions)
+}
+
+func Test_Partition(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := MustNewPool("")
+
+	network, err := pool.CreateNetwork(context.Background())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(network.Close()) })
+
+	_, server, err := runBusybox(context.Background(), WithNetwork(network, "server"))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(server.Close()) })
+
+	_, client, err := runBusybox(context.Background(), WithNetwork(network))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(client.Close()) })
+
+	requestServer := func() (exitCode int) {
+		result, err := client.Exec(context.Background(), "wget", "-q", "-T", "1", "-O", "-", "http://server:"+containerAPIPort)
+		require.NoError(err)
+		return result.ExitCode
+	}
+	require.Zero(requestServer())
+
+	// partition
+	heal, err := pool.Partition(context.Background(), client, server)
+	require.NoError(err)
+	require.NotZero(requestServer())
+	require.Empty(server.GetIPInNetwork(network))
+
+	// heal
+	err = heal(context.Background())
+	require.NoError(err)
+	require.Zero(requestServer())
+	require.NotEmpty(server.GetIPInNetwork(network))
+	require.Contains(server.Container.NetworkSettings.Networks[network.Network.Name].Aliases, "server")
+
+	// no shared networks
+	_, lonely, err := runBusybox(context.Background(), WithNetworkMode("none"), func(options *RunOptions) (err error) {
+		options.ExposedPorts = nil
+		options.Retry.Operation = nil
+		return nil
+	})
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(lonely.Close()) })
+	_, err = pool.Partition(context.Background(), client, lonely)
+	require.ErrorIs(err, ErrInvalidOptions)
+}
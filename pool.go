@@ -0,0 +1,287 @@
+package tcontainer
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/ory/dockertest/v3/docker/opts"
+)
+
+const (
+	defaultDockerContext = "default"
+	dockerContextsDir    = "contexts"
+	dockerConfigFile     = "config.json"
+	dockerEndpointName   = "docker"
+
+	// maxClientAPIVersion - the latest docker API version supported by the client,
+	// newer daemons are used with this version by WithAPIVersionNegotiation.
+	maxClientAPIVersion = "1.44"
+)
+
+type (
+	// dockerEndpoint - resolved address of the docker daemon.
+	dockerEndpoint struct {
+		Host          string
+		TLSCertPath   string
+		SkipTLSVerify bool
+	}
+
+	// dockerContextMeta - `<DockerConfigDir>/contexts/meta/<sha256 of the name>/meta.json` of the docker cli.
+	dockerContextMeta struct {
+		Name      string
+		Endpoints map[string]struct {
+			Host          string
+			SkipTLSVerify bool
+		}
+	}
+)
+
+// NewPoolWithOptions - creates Pool with docker client configured by options (see [PoolOptions]).
+//   - Pings docker daemon and returns ErrDockerUnavailable if it doesn't respond,
+//     so test suites can skip tests when there is no docker.
+//
+// Example usage:
+//
+//	pool, err := tcontainer.NewPoolWithOptions(tcontainer.WithAPIVersionNegotiation())
+//	if errors.Is(err, tcontainer.ErrDockerUnavailable) {
+//		t.Skip(err)
+//	}
+func NewPoolWithOptions(customOpts ...PoolOption) (pool Pool, err error) {
+	options, err := ApplyPoolOptions(customOpts...)
+	if err != nil {
+		return Pool{}, fmt.Errorf("failed to ApplyPoolOptions: %w", err)
+	}
+
+	endpoint, err := resolveDockerEndpoint(options)
+	if err != nil {
+		return Pool{}, fmt.Errorf("failed to resolveDockerEndpoint: %w", err)
+	}
+
+	client, err := newDockerClient(endpoint, options.APIVersion)
+	if err != nil {
+		return Pool{}, fmt.Errorf("failed to newDockerClient: %w", err)
+	}
+
+	if options.PingTimeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), options.PingTimeout)
+		defer cancel()
+
+		err = client.PingWithContext(ctx)
+		if err != nil {
+			return Pool{}, fmt.Errorf("%w: failed to Ping `%s`: %w", ErrDockerUnavailable, endpoint.Host, err)
+		}
+	}
+
+	if options.NegotiateAPIVersion {
+		client, err = negotiateAPIVersion(client, endpoint, options.PingTimeout)
+		if err != nil {
+			return Pool{}, fmt.Errorf("failed to negotiateAPIVersion: %w", err)
+		}
+	}
+
+	pool = newPool(&dockertest.Pool{Client: client, MaxWait: cmp.Or(options.MaxWait, defaultPoolMaxWait)})
+	pool.maxWait = options.MaxWait
+
+	return pool.WithLogger(options.Logger).WithInstrumentation(options.Instrumentation), nil
+}
+
+// resolveDockerEndpoint - resolves docker daemon address in order described in [PoolOptions].
+func resolveDockerEndpoint(options PoolOptions) (endpoint dockerEndpoint, err error) {
+	if options.Endpoint != "" {
+		return dockerEndpoint{Host: options.Endpoint, TLSCertPath: options.TLSCertPath, SkipTLSVerify: false}, nil
+	}
+
+	configDir := options.DockerConfigDir
+	if configDir == "" {
+		configDir = defaultDockerConfigDir()
+	}
+
+	if options.DockerContext != "" {
+		return loadDockerContext(configDir, options.DockerContext, options.TLSCertPath)
+	}
+
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		certPath := options.TLSCertPath
+		if certPath == "" && os.Getenv("DOCKER_TLS_VERIFY") != "" {
+			certPath = cmp.Or(os.Getenv("DOCKER_CERT_PATH"), configDir)
+		}
+
+		return dockerEndpoint{Host: host, TLSCertPath: certPath, SkipTLSVerify: false}, nil
+	}
+
+	contextName := os.Getenv("DOCKER_CONTEXT")
+	if contextName == "" {
+		contextName, err = currentDockerContext(configDir)
+		if err != nil {
+			return dockerEndpoint{}, fmt.Errorf("failed to currentDockerContext: %w", err)
+		}
+	}
+
+	return loadDockerContext(configDir, contextName, options.TLSCertPath)
+}
+
+// defaultDockerConfigDir - `DOCKER_CONFIG` env or `~/.docker`, empty if there is no home directory.
+func defaultDockerConfigDir() string {
+	if configDir := os.Getenv("DOCKER_CONFIG"); configDir != "" {
+		return configDir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".docker")
+}
+
+// currentDockerContext - returns `currentContext` of the docker cli config, empty if there is no config.
+func currentDockerContext(configDir string) (name string, err error) {
+	if configDir == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, dockerConfigFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to ReadFile: %w", err)
+	}
+
+	var config struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", dockerConfigFile, err)
+	}
+
+	return config.CurrentContext, nil
+}
+
+// loadDockerContext - returns endpoint of the docker context, default docker socket for the "default" context.
+//   - certPath overrides TLS certificates of the context.
+func loadDockerContext(configDir, name, certPath string) (endpoint dockerEndpoint, err error) {
+	if name == "" || name == defaultDockerContext {
+		return dockerEndpoint{Host: opts.DefaultHost, TLSCertPath: certPath, SkipTLSVerify: false}, nil
+	}
+
+	digest := sha256.Sum256([]byte(name))
+	contextID := hex.EncodeToString(digest[:])
+
+	data, err := os.ReadFile(filepath.Join(configDir, dockerContextsDir, "meta", contextID, "meta.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return dockerEndpoint{}, fmt.Errorf("%w: docker context `%s` not found in `%s`", ErrInvalidOptions, name, configDir)
+	}
+	if err != nil {
+		return dockerEndpoint{}, fmt.Errorf("failed to ReadFile: %w", err)
+	}
+
+	var meta dockerContextMeta
+	err = json.Unmarshal(data, &meta)
+	if err != nil {
+		return dockerEndpoint{}, fmt.Errorf("failed to parse meta of the docker context `%s`: %w", name, err)
+	}
+
+	dockerMeta, ok := meta.Endpoints[dockerEndpointName]
+	if !ok || dockerMeta.Host == "" {
+		return dockerEndpoint{}, fmt.Errorf("%w: docker context `%s` has no docker endpoint", ErrInvalidOptions, name)
+	}
+
+	if certPath == "" {
+		tlsDir := filepath.Join(configDir, dockerContextsDir, "tls", contextID, dockerEndpointName)
+		if _, err := os.Stat(tlsDir); err == nil {
+			certPath = tlsDir
+		}
+	}
+
+	return dockerEndpoint{Host: dockerMeta.Host, TLSCertPath: certPath, SkipTLSVerify: dockerMeta.SkipTLSVerify}, nil
+}
+
+// newDockerClient - creates docker client, TLS client if endpoint has TLSCertPath.
+//   - Empty apiVersion means requests without version prefix (the latest API version of the daemon).
+func newDockerClient(endpoint dockerEndpoint, apiVersion string) (client *docker.Client, err error) {
+	if endpoint.TLSCertPath != "" {
+		ca := filepath.Join(endpoint.TLSCertPath, "ca.pem")
+		if endpoint.SkipTLSVerify {
+			ca = "" // no CA - no verification
+		}
+
+		client, err = docker.NewVersionedTLSClient(
+			endpoint.Host,
+			filepath.Join(endpoint.TLSCertPath, "cert.pem"),
+			filepath.Join(endpoint.TLSCertPath, "key.pem"),
+			ca,
+			apiVersion,
+		)
+	} else {
+		client, err = docker.NewVersionedClient(endpoint.Host, apiVersion)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for `%s`: %w", endpoint.Host, err)
+	}
+
+	// requests use apiVersion prefix if it's set, so there is no need to request server version
+	client.SkipServerVersionCheck = true
+
+	return client, nil
+}
+
+// negotiateAPIVersion - recreates client with API version of the docker daemon
+// or maxClientAPIVersion if the daemon is newer.
+//   - Request to the daemon is limited by timeout if it's set.
+func negotiateAPIVersion(
+	client *docker.Client, endpoint dockerEndpoint, timeout time.Duration,
+) (negotiated *docker.Client, err error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var version struct {
+		APIVersion string `json:"ApiVersion"`
+	}
+	err = dockerRequest(ctx, client, http.MethodGet, "", "/version", nil, &version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to Version `%s`: %w", ErrDockerUnavailable, endpoint.Host, err)
+	}
+
+	apiVersion, err := minAPIVersion(version.APIVersion, maxClientAPIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("docker `%s` reported invalid api version: %w", endpoint.Host, err)
+	}
+
+	return newDockerClient(endpoint, apiVersion)
+}
+
+// minAPIVersion - returns the lowest of the API versions.
+func minAPIVersion(first, second string) (string, error) {
+	firstVersion, err := docker.NewAPIVersion(first)
+	if err != nil {
+		return "", fmt.Errorf("failed to NewAPIVersion `%s`: %w", first, err)
+	}
+	secondVersion, err := docker.NewAPIVersion(second)
+	if err != nil {
+		return "", fmt.Errorf("failed to NewAPIVersion `%s`: %w", second, err)
+	}
+
+	if secondVersion.LessThan(firstVersion) {
+		return second, nil
+	}
+
+	return first, nil
+}
@@ -0,0 +1,224 @@
+package tcontainer
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	defaultPoolMaxWait     = time.Minute
+	defaultPoolPingTimeout = time.Second * 10
+)
+
+type (
+	// PoolOptions for NewPoolWithOptions function.
+	//
+	// Docker endpoint is resolved in the following order:
+	//  1. Endpoint (see WithEndpoint()).
+	//  2. DockerContext (see WithDockerContext()).
+	//  3. `DOCKER_HOST` env.
+	//  4. Docker context from `DOCKER_CONTEXT` env or `currentContext` of the `config.json` in DockerConfigDir.
+	//  5. Default docker socket (`unix:///var/run/docker.sock`, `npipe:////./pipe/docker_engine` on windows).
+	PoolOptions struct {
+		// Endpoint - docker daemon address (e.g. "unix:///var/run/docker.sock", "tcp://127.0.0.1:2376").
+		Endpoint string
+		// TLSCertPath - directory with `ca.pem`, `cert.pem` and `key.pem` for TLS connection.
+		// `DOCKER_CERT_PATH` env is used by default when `DOCKER_TLS_VERIFY` env is set.
+		TLSCertPath string
+		// APIVersion - docker API version to use (e.g. "1.43"), the client doesn't use versioned paths by default.
+		APIVersion string
+		// NegotiateAPIVersion - use API version of the docker daemon or the latest version supported by the client
+		// if the daemon is newer, requires request to the daemon (limited by PingTimeout).
+		NegotiateAPIVersion bool
+		// DockerContext - name of the docker context from `<DockerConfigDir>/contexts` (see `docker context ls`).
+		DockerContext string
+		// DockerConfigDir - directory of the docker cli config. `DOCKER_CONFIG` env or `~/.docker` by default.
+		DockerConfigDir string
+		// MaxWait - max elapsed time of the readiness retries (see [RetryOptions]).
+		//
+		// Default: `0` - limited only by the retry Backoff (15m for the default one)
+		MaxWait time.Duration
+		// PingTimeout - timeout of the eager ping of the docker daemon, `0` disables the ping.
+		// Unavailable daemon is reported by ErrDockerUnavailable.
+		//
+		// Default: `10s`
+		PingTimeout time.Duration
+		// Logger - logger for structured events of the pool operations, nil disables logging (see Pool.WithLogger()).
+		Logger *slog.Logger
+		// Instrumentation - receives operations of the pool for tracing and metrics, nil disables reporting
+		// (see Pool.WithInstrumentation()).
+		Instrumentation Instrumentation
+	}
+
+	// PoolOption - option for NewPoolWithOptions function.
+	// See [ApplyPoolOptions].
+	PoolOption func(options *PoolOptions) (err error)
+)
+
+// WithEndpoint - use docker daemon address instead of resolving it from env and docker context.
+func WithEndpoint(endpoint string) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		if endpoint == "" {
+			return fmt.Errorf("%w: endpoint is required", ErrInvalidOptions)
+		}
+
+		options.Endpoint = endpoint
+
+		return nil
+	}
+}
+
+// WithTLSCertPath - connect with TLS using `ca.pem`, `cert.pem` and `key.pem` from the directory.
+func WithTLSCertPath(certPath string) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		if certPath == "" {
+			return fmt.Errorf("%w: tls cert path is required", ErrInvalidOptions)
+		}
+
+		options.TLSCertPath = certPath
+
+		return nil
+	}
+}
+
+// WithAPIVersion - use specific docker API version (e.g. "1.43").
+func WithAPIVersion(version string) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		_, err = docker.NewAPIVersion(version)
+		if err != nil {
+			return fmt.Errorf("%w: invalid api version `%s`: %w", ErrInvalidOptions, version, err)
+		}
+
+		options.APIVersion = version
+
+		return nil
+	}
+}
+
+// WithAPIVersionNegotiation - use API version of the docker daemon (limited by the version supported by the client).
+// See [PoolOptions].
+func WithAPIVersionNegotiation() PoolOption {
+	return func(options *PoolOptions) (err error) {
+		options.NegotiateAPIVersion = true
+		return nil
+	}
+}
+
+// WithDockerContext - use endpoint of the docker context (see `docker context ls`).
+func WithDockerContext(name string) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		if name == "" {
+			return fmt.Errorf("%w: docker context name is required", ErrInvalidOptions)
+		}
+
+		options.DockerContext = name
+
+		return nil
+	}
+}
+
+// WithDockerConfigDir - use custom directory of the docker cli config to look for docker contexts.
+func WithDockerConfigDir(dir string) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		if dir == "" {
+			return fmt.Errorf("%w: docker config dir is required", ErrInvalidOptions)
+		}
+
+		options.DockerConfigDir = dir
+
+		return nil
+	}
+}
+
+// WithMaxWait - max elapsed time of the readiness retries (not limited by the pool by default).
+func WithMaxWait(maxWait time.Duration) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		if maxWait <= 0 {
+			return fmt.Errorf("%w: max wait must be positive", ErrInvalidOptions)
+		}
+
+		options.MaxWait = maxWait
+
+		return nil
+	}
+}
+
+// WithPingTimeout - timeout of the eager ping of the docker daemon (10s by default), `0` disables the ping.
+func WithPingTimeout(timeout time.Duration) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		if timeout < 0 {
+			return fmt.Errorf("%w: ping timeout can't be negative", ErrInvalidOptions)
+		}
+
+		options.PingTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithLogger - write structured events of the pool operations to the logger. See [Pool.WithLogger].
+func WithLogger(logger *slog.Logger) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		options.Logger = logger
+		return nil
+	}
+}
+
+// WithInstrumentation - report operations of the pool to the instrumentation. See [Pool.WithInstrumentation].
+func WithInstrumentation(instrumentation Instrumentation) PoolOption {
+	return func(options *PoolOptions) (err error) {
+		options.Instrumentation = instrumentation
+		return nil
+	}
+}
+
+// ApplyPoolOptions sets defaults and apply custom options.
+// Options aplies in order they passed.
+//
+// Each option rewrites previous value.
+func ApplyPoolOptions(customOpts ...PoolOption) (options PoolOptions, err error) {
+	options = options.getDefault()
+
+	for _, customOpt := range customOpts {
+		err = customOpt(&options)
+		if err != nil {
+			return PoolOptions{}, err
+		}
+	}
+
+	err = options.validate()
+	if err != nil {
+		return PoolOptions{}, fmt.Errorf("failed to options.validate: %w", err)
+	}
+
+	return options, nil
+}
+
+func (o PoolOptions) getDefault() (defaultPoolOptions PoolOptions) {
+	return PoolOptions{
+		Endpoint:            "",
+		TLSCertPath:         "",
+		APIVersion:          "",
+		NegotiateAPIVersion: false,
+		DockerContext:       "",
+		DockerConfigDir:     "",
+		MaxWait:             0,
+		PingTimeout:         defaultPoolPingTimeout,
+		Logger:              nil,
+		Instrumentation:     nil,
+	}
+}
+
+func (o PoolOptions) validate() (err error) {
+	if o.Endpoint != "" && o.DockerContext != "" {
+		return fmt.Errorf("%w: endpoint and docker context can't be used together", ErrOptionConflict)
+	}
+	if o.APIVersion != "" && o.NegotiateAPIVersion {
+		return fmt.Errorf("%w: api version and api version negotiation can't be used together", ErrOptionConflict)
+	}
+
+	return nil
+}
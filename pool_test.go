@@ -0,0 +1,380 @@
+package tcontainer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiteggrad/tcontainer/dockerfake"
+)
+
+func Test_ApplyPoolOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		opts  []PoolOption
+		check func(require *require.Assertions, options PoolOptions)
+		err   error
+	}{
+		{
+			name: "default",
+			opts: nil,
+			check: func(require *require.Assertions, options PoolOptions) {
+				require.Zero(options.MaxWait)
+				require.Equal(defaultPoolPingTimeout, options.PingTimeout)
+				require.Empty(options.Endpoint)
+				require.False(options.NegotiateAPIVersion)
+			},
+		},
+		{
+			name: "custom",
+			opts: []PoolOption{
+				WithEndpoint("tcp://127.0.0.1:2376"),
+				WithTLSCertPath("/certs"),
+				WithAPIVersion("1.41"),
+				WithMaxWait(time.Second),
+				WithPingTimeout(0),
+				WithLogger(slog.Default()),
+			},
+			check: func(require *require.Assertions, options PoolOptions) {
+				require.Equal(slog.Default(), options.Logger)
+				require.Equal("tcp://127.0.0.1:2376", options.Endpoint)
+				require.Equal("/certs", options.TLSCertPath)
+				require.Equal("1.41", options.APIVersion)
+				require.Equal(time.Second, options.MaxWait)
+				require.Zero(options.PingTimeout)
+			},
+		},
+		{
+			name: "WithAPIVersion/invalid",
+			opts: []PoolOption{WithAPIVersion("latest")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithMaxWait/zero",
+			opts: []PoolOption{WithMaxWait(0)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithPingTimeout/negative",
+			opts: []PoolOption{WithPingTimeout(-time.Second)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithEndpoint/empty",
+			opts: []PoolOption{WithEndpoint("")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "conflict/endpoint_and_context",
+			opts: []PoolOption{WithEndpoint("unix:///var/run/docker.sock"), WithDockerContext("remote")},
+			err:  ErrOptionConflict,
+		},
+		{
+			name: "conflict/version_and_negotiation",
+			opts: []PoolOption{WithAPIVersion("1.41"), WithAPIVersionNegotiation()},
+			err:  ErrOptionConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			options, err := ApplyPoolOptions(tc.opts...)
+			if tc.err != nil {
+				require.ErrorIs(err, tc.err)
+				return
+			}
+			require.NoError(err)
+			tc.check(require, options)
+		})
+	}
+}
+
+func Test_NewPoolWithOptions(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T) *dockerfake.Server {
+		t.Helper()
+		server := dockerfake.NewServer()
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	t.Run("Endpoint", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		server := newServer(t)
+
+		pool, err := NewPoolWithOptions(WithEndpoint(server.URL()), WithMaxWait(time.Second))
+		require.NoError(err)
+		require.Equal(server.URL(), pool.Pool.Client.Endpoint())
+		require.Equal(time.Second, pool.Pool.MaxWait)
+		require.Equal(time.Second, pool.maxWait)
+		require.Equal([]dockerfake.Request{{Method: http.MethodGet, Path: "/_ping", APIVersion: ""}}, server.Requests())
+	})
+
+	t.Run("APIVersion", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		server := newServer(t)
+
+		pool, err := NewPoolWithOptions(WithEndpoint(server.URL()), WithAPIVersion("1.41"))
+		require.NoError(err)
+		_, err = pool.Pool.Client.ListContainers(docker.ListContainersOptions{}) //nolint:exhaustruct
+		require.NoError(err)
+
+		for _, request := range server.Requests() {
+			require.Equal("1.41", request.APIVersion, request.Path)
+		}
+	})
+
+	t.Run("APIVersionNegotiation", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		server := newServer(t)
+
+		pool, err := NewPoolWithOptions(WithEndpoint(server.URL()), WithAPIVersionNegotiation())
+		require.NoError(err)
+		_, err = pool.Pool.Client.ListContainers(docker.ListContainersOptions{}) //nolint:exhaustruct
+		require.NoError(err)
+
+		requests := server.Requests()
+		require.Equal("/version", requests[len(requests)-2].Path)
+		require.Equal(dockerfake.Request{Method: http.MethodGet, Path: "/containers/json", APIVersion: dockerfake.APIVersion},
+			requests[len(requests)-1])
+	})
+
+	t.Run("APIVersionNegotiation/timeout", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		server := newServer(t)
+		server.Fail(dockerfake.Failure{Method: http.MethodGet, Path: "^/version$", Delay: time.Second})
+
+		_, err := NewPoolWithOptions(
+			WithEndpoint(server.URL()), WithAPIVersionNegotiation(), WithPingTimeout(100*time.Millisecond),
+		)
+		require.ErrorIs(err, ErrDockerUnavailable)
+		require.ErrorIs(err, context.DeadlineExceeded)
+	})
+
+	t.Run("DockerContext", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		server := newServer(t)
+		configDir := t.TempDir()
+		writeDockerContext(t, configDir, "fake", server.URL())
+
+		pool, err := NewPoolWithOptions(WithDockerConfigDir(configDir), WithDockerContext("fake"))
+		require.NoError(err)
+		require.Equal(server.URL(), pool.Pool.Client.Endpoint())
+
+		_, err = NewPoolWithOptions(WithDockerConfigDir(configDir), WithDockerContext("missing"))
+		require.ErrorIs(err, ErrInvalidOptions)
+	})
+
+	t.Run("Unavailable/ping_failure", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		server := newServer(t)
+		server.Fail(dockerfake.Failure{Method: http.MethodGet, Path: `^/_ping$`, Status: http.StatusServiceUnavailable})
+
+		_, err := NewPoolWithOptions(WithEndpoint(server.URL()))
+		require.ErrorIs(err, ErrDockerUnavailable)
+
+		// ping is disabled
+		_, err = NewPoolWithOptions(WithEndpoint(server.URL()), WithPingTimeout(0))
+		require.NoError(err)
+	})
+
+	t.Run("Unavailable/closed", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		server := newServer(t)
+		server.Close()
+
+		_, err := NewPoolWithOptions(WithEndpoint(server.URL()), WithPingTimeout(time.Second))
+		require.ErrorIs(err, ErrDockerUnavailable)
+	})
+}
+
+func Test_minAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		first    string
+		second   string
+		expected string
+	}{
+		{name: "first", first: "1.41", second: "1.44", expected: "1.41"},
+		{name: "second", first: "1.47", second: "1.44", expected: "1.44"},
+		{name: "equal", first: "1.44", second: "1.44", expected: "1.44"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			apiVersion, err := minAPIVersion(tc.first, tc.second)
+			require.NoError(err)
+			require.Equal(tc.expected, apiVersion)
+		})
+	}
+
+	_, err := minAPIVersion("", maxClientAPIVersion)
+	require.Error(t, err)
+}
+
+func Test_resolveDockerEndpoint(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	require := require.New(t)
+
+	configDir := t.TempDir()
+	writeDockerContext(t, configDir, "current", "tcp://current:2375")
+	writeDockerContext(t, configDir, "from-env", "tcp://from-env:2375")
+	config := []byte(`{"currentContext":"current"}`)
+	require.NoError(os.WriteFile(filepath.Join(configDir, dockerConfigFile), config, 0o600))
+
+	t.Setenv("DOCKER_CONFIG", configDir)
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+
+	options, err := ApplyPoolOptions()
+	require.NoError(err)
+
+	// currentContext of the config
+	endpoint, err := resolveDockerEndpoint(options)
+	require.NoError(err)
+	require.Equal("tcp://current:2375", endpoint.Host)
+
+	// DOCKER_CONTEXT overrides currentContext
+	t.Setenv("DOCKER_CONTEXT", "from-env")
+	endpoint, err = resolveDockerEndpoint(options)
+	require.NoError(err)
+	require.Equal("tcp://from-env:2375", endpoint.Host)
+
+	// DOCKER_HOST overrides docker context
+	t.Setenv("DOCKER_HOST", "tcp://docker-host:2376")
+	t.Setenv("DOCKER_TLS_VERIFY", "1")
+	t.Setenv("DOCKER_CERT_PATH", "/certs")
+	endpoint, err = resolveDockerEndpoint(options)
+	require.NoError(err)
+	require.Equal(dockerEndpoint{Host: "tcp://docker-host:2376", TLSCertPath: "/certs", SkipTLSVerify: false}, endpoint)
+
+	// "default" context is the default socket
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", defaultDockerContext)
+	endpoint, err = resolveDockerEndpoint(options)
+	require.NoError(err)
+	require.NotEmpty(endpoint.Host)
+	require.Empty(endpoint.TLSCertPath)
+}
+
+// writeDockerContext - writes docker context as `docker context create` does.
+func writeDockerContext(t *testing.T, configDir, name, host string) {
+	t.Helper()
+
+	digest := sha256.Sum256([]byte(name))
+	metaDir := filepath.Join(configDir, dockerContextsDir, "meta", hex.EncodeToString(digest[:]))
+	require.NoError(t, os.MkdirAll(metaDir, 0o700))
+
+	meta := `{"Name":"` + name + `","Metadata":{},"Endpoints":{"docker":{"Host":"` + host + `","SkipTLSVerify":false}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o600))
+}
+
+func Test_Pool_WithDefaults(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server := dockerfake.NewServer()
+	t.Cleanup(server.Close)
+
+	pool := NewPoolWithClient(server.Client())
+	derived := pool.
+		WithDefaults(WithContainerLabels(map[string]string{"team": "billing"}), WithEnv("A", "default")).
+		WithDefaults(WithEnv("B", "default"))
+
+	// call options are applied after defaults
+	container, err := derived.Run(ctx, "busybox", WithContainerName(t.Name()), WithEnv("A", "custom"))
+	require.NoError(err)
+	require.Equal("billing", container.Container.Config.Labels["team"])
+	require.Equal(DefaultLabelKeyValue, container.Container.Config.Labels[DefaultLabelKeyValue])
+	require.Subset(container.Container.Config.Env, []string{"A=custom", "B=default"})
+
+	// parent pool isn't changed
+	container, err = pool.Run(ctx, "busybox", WithContainerName(t.Name(), "parent"))
+	require.NoError(err)
+	require.NotContains(container.Container.Config.Labels, "team")
+
+	// defaults are dropped
+	container, err = derived.WithoutDefaults().Run(ctx, "busybox", WithContainerName(t.Name(), "without"))
+	require.NoError(err)
+	require.NotContains(container.Container.Config.Labels, "team")
+
+	// build defaults
+	image, err := pool.WithBuildDefaults(WithLabels(map[string]string{"team": "billing"})).BuildAndGet(ctx,
+		WithContextDir("internal/testing"),
+		WithDockerfile("Dockerfile.test"),
+	)
+	require.NoError(err)
+	require.Equal("billing", image.Config.Labels["team"])
+}
+
+func Test_Pool_WithDefaults_ParallelRetry(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	server := dockerfake.NewServer()
+	t.Cleanup(server.Close)
+
+	// the first attempt fails, so each Run uses the shared backoff
+	attempted := sync.Map{}
+	retryBackoff := backoff.NewExponentialBackOff()
+	retryBackoff.InitialInterval = time.Millisecond
+	pool := NewPoolWithClient(server.Client()).WithDefaults(WithRetry(
+		func(_ context.Context, container *Container) (err error) {
+			if _, loaded := attempted.LoadOrStore(container.Container.ID, true); !loaded {
+				return errors.New("not ready")
+			}
+			return nil
+		},
+		retryBackoff,
+	))
+
+	const runs = 10
+	errs := make(chan error, runs)
+	for range runs {
+		go func() {
+			_, err := pool.Run(ctx, "busybox")
+			errs <- err
+		}()
+	}
+	for range runs {
+		require.NoError(<-errs)
+	}
+}
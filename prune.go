@@ -4,38 +4,47 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
 
 	"github.com/ory/dockertest/v3/docker"
 )
 
-// Prune - remove containers and images created by this package.
+// Prune - remove containers, volumes, networks and images created by this package.
+//   - Returns joined errors of all failed removals, resources that were removed concurrently are ignored.
+//   - Prune is reported to the instrumentation of the pool (see WithInstrumentation()).
 func (p Pool) Prune(ctx context.Context, customOptions ...PruneOption) (err error) {
+	ctx, end := p.instrument().Start(ctx, OperationPrune, OperationInfo{ContainerName: "", Image: ""})
+	defer func() { end(OperationResult{Err: err, ContainerID: "", Origin: ""}) }()
+
+	err = p.pruneContainers(ctx, customOptions...)
+	if err != nil {
+		err = fmt.Errorf("failed to pruneContainers: %w", err)
+	}
+
+	// volumes, networks and images can be removed only after containers that use them
 	mu := &sync.Mutex{}
 	wg := &sync.WaitGroup{}
-
-	wg.Add(2) //nolint:mnd
-	go func() {
-		defer wg.Done()
-		removeErr := p.pruneContainers(ctx, customOptions...)
-		if removeErr != nil {
-			mu.Lock()
-			err = errors.Join(err, fmt.Errorf("failed to pruneContainers: %w", removeErr))
-			mu.Unlock()
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		removeErr := p.pruneImages(ctx)
-		if removeErr != nil {
-			mu.Lock()
-			err = errors.Join(err, fmt.Errorf("failed to pruneImages: %w", removeErr))
-			mu.Unlock()
-		}
-	}()
+	for name, prune := range map[string]func(context.Context, ...PruneOption) error{
+		"pruneVolumes":  p.pruneVolumes,
+		"pruneNetworks": p.pruneNetworks,
+		"pruneImages":   p.pruneImages,
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			removeErr := prune(ctx, customOptions...)
+			if removeErr != nil {
+				mu.Lock()
+				err = errors.Join(err, fmt.Errorf("failed to %s: %w", name, removeErr))
+				mu.Unlock()
+			}
+		}()
+	}
 	wg.Wait()
 
-	return nil
+	return err
 }
 
 func (p Pool) pruneContainers(ctx context.Context, customOptions ...PruneOption) (err error) {
@@ -69,16 +78,20 @@ func (p Pool) pruneContainers(ctx context.Context, customOptions ...PruneOption)
 				Force:         true,
 				Context:       ctx,
 			})
-			if removeErr != nil {
+			if removeErr != nil && !errors.As(removeErr, ptr((*docker.NoSuchContainer)(nil))) {
 				mu.Lock()
 				err = errors.Join(err, fmt.Errorf("failed to RemoveContainer `%s`: %w", container.ID, removeErr))
 				mu.Unlock()
+			} else if removeErr == nil {
+				p.log().InfoContext(ctx, "container pruned", containerLogAttrs(
+					strings.Join(container.Names, ","), container.ID, container.Image,
+				)...)
 			}
 		}()
 	}
 	wg.Wait()
 
-	return nil
+	return err
 }
 
 func (p Pool) pruneImages(ctx context.Context, customOptions ...PruneOption) (err error) {
@@ -109,14 +122,97 @@ func (p Pool) pruneImages(ctx context.Context, customOptions ...PruneOption) (er
 				NoPrune: false,
 				Context: ctx,
 			})
-			if removeErr != nil {
+			if removeErr != nil && !errors.Is(removeErr, docker.ErrNoSuchImage) {
 				mu.Lock()
 				err = errors.Join(err, fmt.Errorf("failed to RemoveImageExtended `%s`: %w", image.ID, removeErr))
 				mu.Unlock()
+			} else if removeErr == nil {
+				p.log().InfoContext(ctx, "image pruned",
+					slog.String("image_id", image.ID), slog.Any("tags", image.RepoTags))
+			}
+		}()
+	}
+	wg.Wait()
+
+	return err
+}
+
+func (p Pool) pruneVolumes(ctx context.Context, customOptions ...PruneOption) (err error) {
+	options, err := ApplyPruneOptions(customOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to applyPruneOptions: %w", err)
+	}
+
+	volumes, err := p.Pool.Client.ListVolumes(docker.ListVolumesOptions{
+		Filters: options.PruneVolumesOption.Filters,
+		Context: ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ListVolumes: %w", err)
+	}
+
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for _, volume := range volumes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			removeErr := p.Pool.Client.RemoveVolumeWithOptions(docker.RemoveVolumeOptions{
+				Context: ctx,
+				Name:    volume.Name,
+				Force:   true,
+			})
+			if removeErr != nil && !errors.Is(removeErr, docker.ErrNoSuchVolume) {
+				mu.Lock()
+				err = errors.Join(err, fmt.Errorf("failed to RemoveVolumeWithOptions `%s`: %w", volume.Name, removeErr))
+				mu.Unlock()
+			} else if removeErr == nil {
+				p.log().InfoContext(ctx, "volume pruned", slog.String("volume", volume.Name))
+			}
+		}()
+	}
+	wg.Wait()
+
+	return err
+}
+
+func (p Pool) pruneNetworks(ctx context.Context, customOptions ...PruneOption) (err error) {
+	options, err := ApplyPruneOptions(customOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to applyPruneOptions: %w", err)
+	}
+
+	filters := make(docker.NetworkFilterOpts, len(options.PruneNetworksOption.Filters))
+	for key, values := range options.PruneNetworksOption.Filters {
+		filters[key] = make(map[string]bool, len(values))
+		for _, value := range values {
+			filters[key][value] = true
+		}
+	}
+
+	networks, err := p.Pool.Client.FilteredListNetworks(filters)
+	if err != nil {
+		return fmt.Errorf("failed to FilteredListNetworks: %w", err)
+	}
+
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for _, network := range networks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			removeErr := p.Pool.Client.RemoveNetwork(network.ID)
+			if removeErr != nil && !errors.As(removeErr, ptr((*docker.NoSuchNetwork)(nil))) {
+				mu.Lock()
+				err = errors.Join(err, fmt.Errorf("failed to RemoveNetwork `%s`: %w", network.ID, removeErr))
+				mu.Unlock()
+			} else if removeErr == nil {
+				p.log().InfoContext(ctx, "network pruned",
+					slog.String("network", network.Name), slog.String("network_id", network.ID))
 			}
 		}()
 	}
 	wg.Wait()
 
-	return nil
+	return err
 }
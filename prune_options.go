@@ -9,6 +9,8 @@ type (
 	PruneOptions struct {
 		PruneContainersOption PruneContainersOption
 		PruneImagesOption     PruneImagesOption
+		PruneVolumesOption    PruneVolumesOption
+		PruneNetworksOption   PruneNetworksOption
 	}
 
 	// PruneContainersOption for (Pool).Prune function.
@@ -21,6 +23,16 @@ type (
 		Filters map[string][]string
 	}
 
+	// PruneVolumesOption for (Pool).Prune function.
+	PruneVolumesOption struct {
+		Filters map[string][]string
+	}
+
+	// PruneNetworksOption for (Pool).Prune function.
+	PruneNetworksOption struct {
+		Filters map[string][]string
+	}
+
 	// PruneOption - option for (Pool).Prune function.
 	// See [ApplyPruneOptions].
 	PruneOption func(options *PruneOptions) (err error)
@@ -58,6 +70,12 @@ func (o PruneOptions) getDefault() (defaultPruneOptions PruneOptions) {
 		PruneImagesOption: PruneImagesOption{
 			Filters: map[string][]string{"label": {DefaultLabelKeyValue + "=" + DefaultLabelKeyValue}},
 		},
+		PruneVolumesOption: PruneVolumesOption{
+			Filters: map[string][]string{"label": {DefaultLabelKeyValue + "=" + DefaultLabelKeyValue}},
+		},
+		PruneNetworksOption: PruneNetworksOption{
+			Filters: map[string][]string{"label": {DefaultLabelKeyValue + "=" + DefaultLabelKeyValue}},
+		},
 	}
 }
 
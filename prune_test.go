@@ -85,3 +85,63 @@ func Test_pruneImages(t *testing.T) { //nolint:paralleltest
 	_, err = pool.Pool.Client.InspectImage(sideImage.ID)
 	require.NoError(err)
 }
+
+func Test_pruneVolumes(t *testing.T) { //nolint:paralleltest
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := MustNewPool("")
+
+	// create volume using this package
+	volume, err := pool.Pool.Client.CreateVolume(docker.CreateVolumeOptions{
+		Labels:  newVolumeOptions().Labels,
+		Context: context.Background(),
+	})
+	require.NoError(err)
+
+	// create some side volume
+	sideVolume, err := pool.Pool.Client.CreateVolume(docker.CreateVolumeOptions{Context: context.Background()})
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(pool.Pool.Client.RemoveVolume(sideVolume.Name)) })
+
+	// prune
+	err = pool.pruneVolumes(context.Background())
+	require.NoError(err)
+
+	// check volume was deleted
+	_, err = pool.Pool.Client.InspectVolume(volume.Name)
+	require.ErrorIs(err, docker.ErrNoSuchVolume)
+
+	// check side volume wasn't deleted
+	_, err = pool.Pool.Client.InspectVolume(sideVolume.Name)
+	require.NoError(err)
+}
+
+func Test_pruneNetworks(t *testing.T) { //nolint:paralleltest
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := MustNewPool("")
+
+	// create network using this package
+	network, err := pool.CreateNetwork(context.Background())
+	require.NoError(err)
+
+	// create some side network
+	sideNetwork, err := pool.Pool.CreateNetwork(t.Name())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(sideNetwork.Close()) })
+
+	// prune
+	err = pool.pruneNetworks(context.Background())
+	require.NoError(err)
+
+	// check network was deleted
+	_, err = pool.Pool.Client.NetworkInfo(network.Network.ID)
+	var noSuchNetworkErr *docker.NoSuchNetwork
+	require.ErrorAs(err, &noSuchNetworkErr)
+
+	// check side network wasn't deleted
+	_, err = pool.Pool.Client.NetworkInfo(sideNetwork.Network.ID)
+	require.NoError(err)
+}
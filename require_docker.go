@@ -0,0 +1,121 @@
+package tcontainer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// MissingDockerEnv - env that defines behavior of RequireDocker and PoolForTest when docker is unavailable:
+	//   - MissingDockerSkip ("skip", default) - skip the test, e.g. on developer laptops without docker.
+	//   - MissingDockerFail ("fail") - fail the test, e.g. in CI where docker must be available.
+	MissingDockerEnv = "TCONTAINER_MISSING_DOCKER"
+	// MissingDockerSkip - skip the test when docker is unavailable. See [MissingDockerEnv].
+	MissingDockerSkip = "skip"
+	// MissingDockerFail - fail the test when docker is unavailable. See [MissingDockerEnv].
+	MissingDockerFail = "fail"
+)
+
+// TestingT - subset of testing.TB used by RequireDocker and PoolForTest.
+type TestingT interface {
+	Helper()
+	Skip(args ...any)
+	Fatal(args ...any)
+}
+
+//nolint:gochecknoglobals // docker availability is checked once per process
+var dockerCheck struct {
+	once sync.Once
+	pool Pool
+	err  error
+}
+
+// CheckDocker - creates Pool by NewPool("") and pings docker daemon.
+//   - Returns error wrapping ErrDockerUnavailable if docker doesn't respond.
+//   - The check runs once per process, the result is cached.
+//
+// Useful in TestMain, use RequireDocker or PoolForTest in tests.
+func CheckDocker() (pool Pool, err error) {
+	dockerCheck.once.Do(func() {
+		dockerCheck.pool, dockerCheck.err = checkDocker()
+	})
+
+	return dockerCheck.pool, dockerCheck.err
+}
+
+// RequireDocker - skips or fails the test if docker is unavailable (see [MissingDockerEnv]).
+//
+// Example usage:
+//
+//	func Test_WithDocker(t *testing.T) {
+//		tcontainer.RequireDocker(t)
+//		...
+//	}
+func RequireDocker(t TestingT) {
+	t.Helper()
+
+	_ = PoolForTest(t)
+}
+
+// PoolForTest - returns Pool of the available docker, skips or fails the test
+// if docker is unavailable (see [MissingDockerEnv]).
+//
+// Example usage:
+//
+//	pool := tcontainer.PoolForTest(t)
+//	container, err := pool.Run(ctx, "nginx")
+func PoolForTest(t TestingT) Pool {
+	t.Helper()
+
+	pool, err := CheckDocker()
+
+	return poolForTest(t, pool, err, os.Getenv(MissingDockerEnv))
+}
+
+func checkDocker() (pool Pool, err error) {
+	pool, err = NewPool("")
+	if err != nil {
+		return Pool{}, fmt.Errorf("%w: failed to NewPool: %w", ErrDockerUnavailable, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPoolPingTimeout)
+	defer cancel()
+
+	err = pool.Pool.Client.PingWithContext(ctx)
+	if err != nil {
+		return Pool{}, fmt.Errorf("%w: failed to Ping `%s`: %w", ErrDockerUnavailable, pool.Pool.Client.Endpoint(), err)
+	}
+
+	return pool, nil
+}
+
+// poolForTest - returns the pool if there is no checkErr, otherwise skips or fails the test depending on the mode.
+func poolForTest(t TestingT, pool Pool, checkErr error, mode string) Pool {
+	t.Helper()
+
+	switch mode {
+	case "", MissingDockerSkip, MissingDockerFail:
+	default:
+		t.Fatal(fmt.Sprintf(
+			"invalid %s=%s, expected `%s` or `%s`", MissingDockerEnv, mode, MissingDockerSkip, MissingDockerFail,
+		))
+		return Pool{}
+	}
+
+	if checkErr == nil {
+		return pool
+	}
+
+	if mode == MissingDockerFail {
+		t.Fatal(fmt.Sprintf("test requires docker: %s", checkErr))
+		return Pool{}
+	}
+
+	t.Skip(fmt.Sprintf(
+		"test requires docker: %s (set %s=%s to fail instead)", checkErr, MissingDockerEnv, MissingDockerFail,
+	))
+
+	return Pool{}
+}
@@ -0,0 +1,88 @@
+package tcontainer_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiteggrad/tcontainer"
+	tcontainer_mocks "github.com/kiteggrad/tcontainer/mocks"
+)
+
+func Test_poolForTest(t *testing.T) {
+	t.Parallel()
+
+	pool := tcontainer.NewPoolWithClient(nil)
+	checkErr := fmt.Errorf("%w: failed to Ping", tcontainer.ErrDockerUnavailable)
+
+	testCases := []struct {
+		name          string
+		checkErr      error
+		mode          string
+		expectedPool  bool
+		expectedSkip  string
+		expectedFatal string
+	}{
+		{
+			name:         "available",
+			checkErr:     nil,
+			mode:         "",
+			expectedPool: true,
+		},
+		{
+			name:         "unavailable/default",
+			checkErr:     checkErr,
+			mode:         "",
+			expectedSkip: "test requires docker: docker is unavailable: failed to Ping (set TCONTAINER_MISSING_DOCKER=fail",
+		},
+		{
+			name:         "unavailable/skip",
+			checkErr:     checkErr,
+			mode:         tcontainer.MissingDockerSkip,
+			expectedSkip: "test requires docker: docker is unavailable: failed to Ping",
+		},
+		{
+			name:          "unavailable/fail",
+			checkErr:      checkErr,
+			mode:          tcontainer.MissingDockerFail,
+			expectedFatal: "test requires docker: docker is unavailable: failed to Ping",
+		},
+		{
+			name:          "invalid_mode",
+			checkErr:      nil,
+			mode:          "ignore",
+			expectedFatal: "invalid TCONTAINER_MISSING_DOCKER=ignore",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			// unexpected Skip or Fatal fails the test
+			mockT := tcontainer_mocks.NewTestingT(t)
+			mockT.EXPECT().Helper().Return()
+			if tc.expectedSkip != "" {
+				mockT.EXPECT().Skip(mock.MatchedBy(func(message string) bool {
+					return strings.Contains(message, tc.expectedSkip)
+				})).Return().Once()
+			}
+			if tc.expectedFatal != "" {
+				mockT.EXPECT().Fatal(mock.MatchedBy(func(message string) bool {
+					return strings.Contains(message, tc.expectedFatal)
+				})).Return().Once()
+			}
+
+			result := tcontainer.PoolForTestWithCheck(mockT, pool, tc.checkErr, tc.mode)
+
+			if tc.expectedPool {
+				require.Equal(pool, result)
+			} else {
+				require.Nil(result.Pool)
+			}
+		})
+	}
+}
@@ -4,131 +4,727 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 )
 
+// defaultCleanupTimeout - timeout of the removal of the container that failed to start or get ready.
+const defaultCleanupTimeout = time.Second * 30
+
 // Run - creates and runs new test container.
+//   - Default options of the Pool (see WithDefaults()) are applied before customOpts.
+//   - Cancellation of the ctx interrupts image pull, container creation, start and readiness retries,
+//     partially created container is removed and returned error wraps ctx.Err().
+//   - Errors of the container startup are returned as *RunError with the phase, name, image and logs of the container.
+//   - Run and its startup phases are reported to the instrumentation of the pool (see WithInstrumentation()).
+//   - Durations of the startup phases are available by (*Container).StartupStats() and Pool.Stats().
 func (p Pool) Run(
 	ctx context.Context, repository string, customOpts ...RunOption,
-) (container *dockertest.Resource, err error) {
-	options, err := ApplyRunOptions(repository, customOpts...)
+) (container *Container, err error) {
+	start := time.Now()
+
+	options, err := ApplyRunOptions(repository, append(slices.Clip(p.runDefaults), customOpts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to applyTestContainerOptions: %w", err)
 	}
 
-	return p.run(ctx, options)
+	ctx, end := p.instrument().Start(ctx, OperationRun, options.operationInfo())
+	defer func() {
+		result := OperationResult{Err: err, ContainerID: "", Origin: ""}
+		if container != nil {
+			result.ContainerID, result.Origin = container.Container.ID, container.Outcome().Origin
+		}
+		end(result)
+	}()
+
+	// phases of the run are collected to StartupStats by the instrumentation of the run
+	stats := &StartupStats{} //nolint:exhaustruct
+	runPool := p
+	runPool.instrumentation = newStartupStatsCollector(p.instrument(), stats)
+
+	container, err = runPool.run(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	container.pool = p
+	stats.Total = time.Since(start)
+	container.startupStats = *stats
+	if p.stats != nil {
+		p.stats.add(containerImage(container.Container), container.outcome.Origin, *stats)
+	}
+
+	return container, nil
 }
 
 func (p Pool) run(
 	ctx context.Context, options RunOptions,
-) (container *dockertest.Resource, err error) {
-	container, err = p.initContainer(ctx, options)
+) (container *Container, err error) {
+	ctx, cancel := options.startupContext(ctx)
+	defer cancel()
+
+	// phase in progress for errors caused by StartupTimeout and RunError
+	phase := StartupPhaseCreate
+	// started container to remove on error
+	var started *Container
+	// named volumes created with the container (see WithVolume), removed on error with the container
+	var volumes []string
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		err = startupPhaseError(ctx, phase, err)
+		if ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+			err = fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+
+		runErr := p.newRunError(ctx, phase, options, started, err)
+		err = runErr
+		p.log().WarnContext(ctx, "failed to run container", containerLogAttrs(
+			runErr.Name, runErr.ContainerID, runErr.Image,
+			slog.String("phase", string(runErr.Phase)), slog.Any(logKeyError, runErr.Err),
+		)...)
+		if started != nil {
+			p.cleanup(ctx, started)
+		} else if len(volumes) != 0 {
+			p.purgeVolumes(ctx, volumes)
+		}
+	}()
+
+	restoredFromSnapshot := false
+	if options.RestoreSnapshot.Name != "" {
+		options, restoredFromSnapshot, err = p.applyRestoreSnapshot(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to applyRestoreSnapshot: %w", err)
+		}
+	}
+
+	if len(options.Hooks.BeforeCreate) != 0 {
+		for _, hook := range options.Hooks.BeforeCreate {
+			err = hook(ctx, &options)
+			if err != nil {
+				return nil, fmt.Errorf("failed to run BeforeCreate hook: %w", err)
+			}
+		}
+
+		err = options.validate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to options.validate after BeforeCreate hooks: %w", err)
+		}
+	}
+
+	volumes, err = p.newVolumes(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to newVolumes: %w", err)
+	}
+
+	phase = StartupPhaseStart
+	resource, outcome, err := p.initContainer(ctx, options, restoredFromSnapshot)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initContainer: %w", err)
 	}
+	container = &Container{
+		Resource: resource, pool: p, options: options, outcome: outcome, startupStats: StartupStats{}, volumes: volumes,
+	}
+	started = container
+
+	// refresh connected containers, so network.Close() can disconnect them
+	for _, network := range options.Networks {
+		network.Network, err = p.networkInfo(ctx, network.Network.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to networkInfo: %w", err)
+		}
+	}
 
 	if options.ContainerExpiry != 0 {
-		err = container.Expire(uint(options.ContainerExpiry.Seconds()))
+		err = ctx.Err()
 		if err != nil {
-			_ = p.Pool.Purge(container)
-			return nil, fmt.Errorf("failed to container.Expire: %w", err)
+			return nil, err //nolint:wrapcheck
 		}
+		p.expire(ctx, container.Container.ID, uint(options.ContainerExpiry.Seconds()))
+		p.log().InfoContext(ctx, "container expiry scheduled", containerLogAttrs(
+			options.Name, container.Container.ID, options.image(), slog.Duration("expiry", options.ContainerExpiry),
+		)...)
+	}
+
+	err = runHooks(ctx, container, options.Hooks.AfterStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run AfterStart hook: %w", err)
 	}
 
-	if options.Retry.Operation != nil {
-		_, err = backoff.Retry(
-			ctx,
-			func() (_ struct{}, err error) { return struct{}{}, options.Retry.Operation(ctx, container) },
-			backoff.WithBackOff(options.Retry.Backoff),
-		)
+	phase = StartupPhaseReady
+	err = p.runStartupPhase(ctx, options, StartupPhaseReady, func(ctx context.Context) (err error) {
+		err = p.retry(ctx, container, options.Retry)
 		if err != nil {
-			_ = p.Pool.Purge(container)
-			return nil, fmt.Errorf("failed to retry: %w", err)
+			return fmt.Errorf("%w: failed to retry: %w", ErrReadinessFailed, err)
 		}
+
+		err = runHooks(ctx, container, options.Hooks.AfterReady)
+		if err != nil {
+			return fmt.Errorf("failed to run AfterReady hook: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return container, nil
 }
 
+// terminate - purges the container and runs terminate hooks (see (*Container).Terminate).
+//   - Named volumes created with the container are removed too, unless the container is run for reuse.
+func (p Pool) terminate(ctx context.Context, container *Container) (err error) {
+	hooks := container.options.Hooks
+
+	beforeErr := runHooks(ctx, container, hooks.BeforeTerminate)
+	if beforeErr != nil {
+		beforeErr = fmt.Errorf("failed to run BeforeTerminate hook: %w", beforeErr)
+	}
+
+	// container with AutoRemove is already removed after Stop or Kill
+	err = p.removeContainer(ctx, container.Container.ID)
+	if err != nil && !errors.As(err, ptr((*docker.NoSuchContainer)(nil))) {
+		return errors.Join(beforeErr, fmt.Errorf("failed to removeContainer: %w", err))
+	}
+
+	if !container.options.Reuse.Reuse {
+		err = p.removeVolumes(ctx, container.volumes)
+		if err != nil {
+			return errors.Join(beforeErr, fmt.Errorf("failed to removeVolumes: %w", err))
+		}
+	}
+
+	err = runHooks(ctx, container, hooks.AfterTerminate)
+	if err != nil {
+		return errors.Join(beforeErr, fmt.Errorf("failed to run AfterTerminate hook: %w", err))
+	}
+
+	return beforeErr
+}
+
+// cleanup - terminates container that failed to get ready, works even if ctx is already canceled.
+func (p Pool) cleanup(ctx context.Context, container *Container) {
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	_ = p.terminate(ctx, container)
+}
+
+// cleanupContext - returns context for removal of the failed container that isn't canceled with ctx.
+func cleanupContext(ctx context.Context) (cleanupCtx context.Context, cancel context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), defaultCleanupTimeout)
+}
+
+// runHooks - runs hooks in order, stops on the first error.
+func runHooks(ctx context.Context, container *Container, hooks []Hook) (err error) {
+	for _, hook := range hooks {
+		err = hook(ctx, container)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retry - runs retryOptions.Operation with backoff until success (at most PoolOptions.MaxWait if set),
+// does nothing if there is no Operation.
+//   - If ctx is done, returned error wraps ctx.Err() and the last error of the Operation.
+func (p Pool) retry(ctx context.Context, container *Container, retryOptions RetryOptions) (err error) {
+	if retryOptions.Operation == nil {
+		return nil
+	}
+
+	retryOpts := []backoff.RetryOption{backoff.WithBackOff(retryOptions.Backoff)}
+	if p.maxWait > 0 {
+		retryOpts = append(retryOpts, backoff.WithMaxElapsedTime(p.maxWait))
+	}
+
+	attrs := containerLogAttrs(container.Container.Name, container.Container.ID, containerImage(container.Container))
+	info := OperationInfo{
+		ContainerName: strings.TrimPrefix(container.Container.Name, "/"),
+		Image:         containerImage(container.Container),
+	}
+
+	var (
+		attempt int
+		lastErr error
+	)
+	_, err = backoff.Retry(
+		ctx,
+		func() (_ struct{}, err error) {
+			attempt++
+			lastErr = retryOptions.Operation(ctx, container)
+			if lastErr != nil {
+				p.log().DebugContext(ctx, "readiness check attempt failed",
+					append(attrs, slog.Int("attempt", attempt), slog.Any(logKeyError, lastErr))...)
+				p.instrument().RetryAttempt(ctx, info, attempt, lastErr)
+			}
+
+			return struct{}{}, lastErr
+		},
+		retryOpts...,
+	)
+	if err != nil && ctx.Err() != nil && lastErr != nil {
+		return fmt.Errorf("%w, last error: %w", ctx.Err(), lastErr)
+	} else if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	p.log().InfoContext(ctx, "container is ready", append(attrs, slog.Int("attempts", attempt))...)
+
+	return nil
+}
+
 func (p Pool) initContainer(
-	ctx context.Context, options RunOptions,
-) (container *dockertest.Resource, err error) {
-	container, err = p.createAndStartContainer(options)
+	ctx context.Context, options RunOptions, restoredFromSnapshot bool,
+) (container *dockertest.Resource, outcome RunOutcome, err error) {
+	outcome = RunOutcome{Origin: ContainerOriginCreated, ReuseErr: nil, RestoredFromSnapshot: restoredFromSnapshot}
+	container, err = p.createAndStartContainer(ctx, options, outcome)
 	switch {
 	case err == nil:
-		return container, nil
+		return container, outcome, nil
 
 	case errors.Is(err, ErrContainerAlreadyExists) && options.Reuse.Reuse:
-		container, err = p.reuseOrRecreateContainer(ctx, options)
+		p.log().InfoContext(ctx, "container already exists, trying to reuse it",
+			containerLogAttrs(options.Name, "", options.image())...)
+		container, outcome, err = p.reuseOrRecreateContainer(ctx, options, restoredFromSnapshot)
 		if err != nil {
-			return nil, fmt.Errorf("failed to reuseOrRecreateContainer: %w", err)
+			return nil, RunOutcome{}, fmt.Errorf("failed to reuseOrRecreateContainer: %w", err)
 		}
 
-		return container, nil
+		return container, outcome, nil
 
 	case errors.Is(err, ErrContainerAlreadyExists) && options.RemoveOnExists:
-		container, err := p.recreateContainer(options)
+		p.log().InfoContext(ctx, "container already exists, recreating it",
+			containerLogAttrs(options.Name, "", options.image())...)
+		outcome = RunOutcome{Origin: ContainerOriginRecreated, ReuseErr: nil, RestoredFromSnapshot: restoredFromSnapshot}
+		container, err := p.recreateContainer(ctx, options, outcome)
 		if err != nil {
-			return nil, fmt.Errorf("failed to recreateContainer by options.RemoveOnExists: %w", err)
+			return nil, RunOutcome{}, fmt.Errorf("failed to recreateContainer by options.RemoveOnExists: %w", err)
 		}
 
-		return container, nil
+		return container, outcome, nil
 
 	default:
-		return nil, fmt.Errorf("failed to createAndStartContainer: %w", err)
+		return nil, RunOutcome{}, fmt.Errorf("failed to createAndStartContainer: %w", err)
 	}
 }
 
 func (p Pool) createAndStartContainer(
-	options RunOptions,
+	ctx context.Context, options RunOptions, outcome RunOutcome,
 ) (container *dockertest.Resource, err error) {
-	container, err = p.Pool.RunWithOptions(
-		options.toDockertest(),
-		func(hc *docker.HostConfig) { *hc = options.HostConfig },
+	if options.PortInUse.MaxTries <= 1 {
+		return p.createAndStartContainerOnce(ctx, options, outcome)
+	}
+
+	attempt := 0
+	container, err = backoff.Retry(
+		ctx,
+		func() (container *dockertest.Resource, err error) {
+			attempt++
+			container, err = p.createAndStartContainerOnce(ctx, options, outcome)
+			if err != nil && !errors.Is(err, ErrPortInUse) {
+				return nil, backoff.Permanent(err)
+			} else if err != nil {
+				p.log().InfoContext(ctx, "host port is in use, retrying", containerLogAttrs(
+					options.Name, "", options.image(), slog.Int("attempt", attempt), slog.Any(logKeyError, err),
+				)...)
+			}
+
+			return container, err
+		},
+		backoff.WithBackOff(options.PortInUse.Backoff),
+		backoff.WithMaxTries(options.PortInUse.MaxTries),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to dockerPool.RunWithOptions: %w", err)
+		return nil, fmt.Errorf("failed to retry on port in use: %w", err)
 	}
 
 	return container, nil
 }
 
+func (p Pool) createAndStartContainerOnce(
+	ctx context.Context, options RunOptions, outcome RunOutcome,
+) (container *dockertest.Resource, err error) {
+	err = p.runStartupPhase(ctx, options, StartupPhasePull, func(ctx context.Context) (err error) {
+		return p.pullImageIfNotExists(ctx, options)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pullImageIfNotExists: %w", err)
+	}
+
+	var containerID string
+	err = p.runStartupPhase(ctx, options, StartupPhaseCreate, func(ctx context.Context) (err error) {
+		containerID, err = p.createContainer(ctx, options, outcome)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to createContainer: %w", err)
+	}
+
+	err = p.runStartupPhase(ctx, options, StartupPhaseStart, func(ctx context.Context) (err error) {
+		container, err = p.startContainerResource(ctx, containerID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to startContainerResource: %w", err)
+	}
+
+	return container, nil
+}
+
+// createContainer - creates the container and runs AfterCreate hooks, removes the container on error.
+func (p Pool) createContainer(
+	ctx context.Context, options RunOptions, outcome RunOutcome,
+) (containerID string, err error) {
+	createdContainer, err := p.Pool.Client.CreateContainer(options.toCreateContainerOptions(ctx))
+	if err != nil {
+		// docker may create the container after the request was canceled,
+		// but existing container with the same name may be reused later, so it's left for Prune
+		if ctx.Err() != nil && options.Name != "" && !options.Reuse.Reuse {
+			p.purgeContainerByName(ctx, options.Name)
+		}
+
+		return "", fmt.Errorf("failed to CreateContainer: %w", err)
+	}
+
+	p.log().InfoContext(ctx, "container created", containerLogAttrs(options.Name, createdContainer.ID, options.image())...)
+
+	err = p.connectNetworks(ctx, createdContainer.ID, options)
+	if err != nil {
+		p.purgeContainer(ctx, createdContainer.ID)
+		return "", fmt.Errorf("failed to connectNetworks: %w", err)
+	}
+
+	if len(options.Hooks.AfterCreate) != 0 {
+		err = p.runAfterCreateHooks(ctx, createdContainer.ID, options, outcome)
+		if err != nil {
+			return "", fmt.Errorf("failed to runAfterCreateHooks: %w", err)
+		}
+	}
+
+	return createdContainer.ID, nil
+}
+
+// connectNetworks - connects created container to the networks after the first one with their aliases
+// (the first network is joined at creation, see RunOptions.toCreateContainerOptions).
+func (p Pool) connectNetworks(ctx context.Context, containerID string, options RunOptions) (err error) {
+	for _, network := range options.Networks[min(1, len(options.Networks)):] {
+		err = p.Pool.Client.ConnectNetwork(network.Network.ID, docker.NetworkConnectionOptions{
+			Container:      containerID,
+			EndpointConfig: &docker.EndpointConfig{Aliases: options.NetworkAliases[network.Network.ID]}, //nolint:exhaustruct
+			Force:          false,
+			Context:        ctx,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to ConnectNetwork `%s`: %w", network.Network.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// startContainerResource - starts created container and returns it, removes the container on error.
+func (p Pool) startContainerResource(
+	ctx context.Context, containerID string,
+) (container *dockertest.Resource, err error) {
+	err = p.Pool.Client.StartContainerWithContext(containerID, nil, ctx)
+	if err != nil {
+		// never started container isn't removed by AutoRemove
+		p.purgeContainer(ctx, containerID)
+		if isPortInUseErr(err) {
+			err = fmt.Errorf("%w: %w", ErrPortInUse, err)
+		}
+
+		return nil, fmt.Errorf("failed to StartContainer: %w", err)
+	}
+
+	container, err = p.containerResource(ctx, containerID)
+	if err != nil {
+		p.purgeContainer(ctx, containerID)
+		return nil, fmt.Errorf("failed to containerResource: %w", err)
+	}
+
+	p.log().InfoContext(ctx, "container started",
+		containerLogAttrs(container.Container.Name, containerID, containerImage(container.Container))...)
+
+	return container, nil
+}
+
+// runAfterCreateHooks - runs AfterCreate hooks for created (not started) container, terminates it on error.
+func (p Pool) runAfterCreateHooks(
+	ctx context.Context, containerID string, options RunOptions, outcome RunOutcome,
+) (err error) {
+	resource, err := p.containerResource(ctx, containerID)
+	if err != nil {
+		p.purgeContainer(ctx, containerID)
+		return fmt.Errorf("failed to containerResource: %w", err)
+	}
+
+	container := &Container{
+		Resource: resource, pool: p, options: options, outcome: outcome, startupStats: StartupStats{}, volumes: nil,
+	}
+	err = runHooks(ctx, container, options.Hooks.AfterCreate)
+	if err != nil {
+		p.cleanup(ctx, container)
+		return fmt.Errorf("failed to run AfterCreate hook: %w", err)
+	}
+
+	return nil
+}
+
+func (p Pool) pullImageIfNotExists(ctx context.Context, options RunOptions) (err error) {
+	image := options.image()
+
+	_, err = p.inspectImage(ctx, image)
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, docker.ErrNoSuchImage) {
+		return fmt.Errorf("failed to InspectImage: %w", err)
+	}
+
+	auth := options.Auth
+	// private registry like "registry.example.com/team/image" - try to use docker credentials helpers
+	parts := strings.SplitN(options.Repository, "/", 3) //nolint:mnd
+
+	if auth == (docker.AuthConfiguration{}) && len(parts) == 3 { //nolint:exhaustruct,mnd
+		helperAuth, helperErr := docker.NewAuthConfigurationsFromCredsHelpers(parts[0])
+		if helperErr == nil {
+			auth = *helperAuth
+		}
+	}
+
+	p.log().InfoContext(ctx, "pulling image", slog.String(logKeyImage, image), slog.String("platform", options.Platform))
+	pullStart := time.Now()
+
+	err = p.Pool.Client.PullImage(docker.PullImageOptions{ //nolint:exhaustruct
+		Repository: options.Repository,
+		Tag:        strings.TrimPrefix(image, options.Repository+":"),
+		Platform:   options.Platform,
+		Context:    ctx,
+	}, auth)
+	if err != nil {
+		if isImageNotFoundErr(err) {
+			err = fmt.Errorf("%w: %w", ErrImageNotFound, err)
+		}
+
+		return fmt.Errorf("failed to PullImage `%s`: %w", image, err)
+	}
+
+	p.log().InfoContext(ctx, "image pulled",
+		slog.String(logKeyImage, image), slog.Duration("duration", time.Since(pullStart)))
+
+	return nil
+}
+
+// containerResource - returns actual state of the container as Resource bound to the dockertest pool,
+// so methods of the Resource that use the pool (e.g. Close, Exec) work.
+func (p Pool) containerResource(ctx context.Context, containerID string) (container *dockertest.Resource, err error) {
+	inspectedContainer, err := p.inspectContainer(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspectContainer: %w", err)
+	}
+
+	// only dockertest binds the resource to the pool, it has no ctx
+	name := "^/?" + regexp.QuoteMeta(strings.TrimPrefix(inspectedContainer.Name, "/")) + "$"
+	container, err = callWithContext(ctx, func() (*dockertest.Resource, error) {
+		container, ok := p.Pool.ContainerByName(name)
+		if !ok {
+			return nil, &docker.NoSuchContainer{ID: containerID, Err: nil}
+		}
+		return container, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ContainerByName: %w", err)
+	}
+
+	// state with assigned host ports (see inspectContainer)
+	container.Container = inspectedContainer
+
+	return container, nil
+}
+
+// containerByName - returns container with exact name, ok is false if there is no such container.
+func (p Pool) containerByName(
+	ctx context.Context, name string,
+) (container *dockertest.Resource, ok bool, err error) {
+	containers, err := p.Pool.Client.ListContainers(docker.ListContainersOptions{ //nolint:exhaustruct
+		All:     true,
+		Filters: map[string][]string{"name": {fmt.Sprintf("^%s$", name)}},
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to ListContainers: %w", err)
+	}
+	if len(containers) == 0 {
+		return nil, false, nil
+	}
+
+	container, err = p.containerResource(ctx, containers[0].ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to containerResource: %w", err)
+	}
+
+	return container, true, nil
+}
+
+// expire - stops the container in background like (*dockertest.Resource).Expire:
+// stop with expirySignal is ignored by processes, so docker kills the container after the timeout.
+//   - Expiry isn't canceled with ctx, it outlives Run.
+//   - Requires docker API 1.42+ for the stop signal, older daemons reject the request (expiry is logged as failed).
+func (p Pool) expire(ctx context.Context, containerID string, seconds uint) {
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		query := url.Values{"t": {strconv.FormatUint(uint64(seconds), 10)}, "signal": {expirySignal}}
+		path := "/containers/" + containerID + "/stop"
+		err := dockerRequest(ctx, p.Pool.Client, http.MethodPost, expiryAPIVersion, path, query, nil)
+		if err != nil && !isDockerErrorStatus(err, http.StatusNotFound) {
+			p.log().WarnContext(ctx, "failed to expire container",
+				containerLogAttrs("", containerID, "", slog.Any(logKeyError, err))...)
+		}
+	}()
+}
+
+// inspectContainer - returns actual container state.
+//   - Waits until docker assigns host ports for port bindings of the running container.
+func (p Pool) inspectContainer(ctx context.Context, containerID string) (container *docker.Container, err error) {
+	const (
+		maxTries      = 10
+		retryInterval = time.Millisecond * 100
+	)
+
+	isReady := func(container *docker.Container) bool {
+		return !container.State.Running || hasAssignedHostPorts(container)
+	}
+
+	container, err = p.Pool.Client.InspectContainerWithContext(containerID, ctx)
+	for try := 1; err == nil && try < maxTries && !isReady(container); try++ {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for host ports: %w", ctx.Err())
+		case <-time.After(retryInterval):
+		}
+
+		container, err = p.Pool.Client.InspectContainerWithContext(containerID, ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to InspectContainer: %w", err)
+	}
+
+	return container, nil
+}
+
+// hasAssignedHostPorts - checks that all port bindings of the container have assigned host ports
+// (all exposed ports if ports are published by HostConfig.PublishAllPorts).
+func hasAssignedHostPorts(container *docker.Container) bool {
+	if container.NetworkSettings == nil {
+		return false
+	}
+
+	if container.HostConfig == nil {
+		return true
+	}
+
+	ports := slices.Collect(maps.Keys(container.HostConfig.PortBindings))
+	if container.HostConfig.PublishAllPorts && container.Config != nil {
+		ports = append(ports, slices.Collect(maps.Keys(container.Config.ExposedPorts))...)
+	}
+
+	for _, port := range ports {
+		bindings := container.NetworkSettings.Ports[normalizePort(port)]
+		if len(bindings) == 0 {
+			return false
+		}
+		for _, binding := range bindings {
+			if binding.HostPort == "" || binding.HostPort == "0" {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// normalizePort - adds default "tcp" protocol to the port ("80" -> "80/tcp") as docker does.
+func normalizePort(port docker.Port) docker.Port {
+	if strings.Contains(string(port), "/") {
+		return port
+	}
+
+	return port + "/tcp"
+}
+
+// isPortInUseErr - checks that error is caused by host port already used by other process or container.
+func isPortInUseErr(err error) bool {
+	msg := err.Error()
+
+	return strings.Contains(msg, "port is already allocated") ||
+		strings.Contains(msg, "address already in use") ||
+		strings.Contains(msg, "ports are not available")
+}
+
 // reuseOrRecreateContainer - try to reuse container, or recreate (optional) if failed to reuse.
 func (p Pool) reuseOrRecreateContainer(
-	ctx context.Context, options RunOptions,
-) (container *dockertest.Resource, err error) {
-	container, err = p.reuseContainer(ctx, options)
+	ctx context.Context, options RunOptions, restoredFromSnapshot bool,
+) (container *dockertest.Resource, outcome RunOutcome, err error) {
+	container, repaired, err := p.reuseContainer(ctx, options)
 	switch {
 	case err == nil:
-		return container, nil
+		outcome = RunOutcome{Origin: ContainerOriginReused, ReuseErr: nil, RestoredFromSnapshot: restoredFromSnapshot}
+		if repaired {
+			outcome.Origin = ContainerOriginRepaired
+		}
+		p.log().InfoContext(ctx, "container reused", containerLogAttrs(
+			options.Name, container.Container.ID, options.image(), slog.Bool("repaired", repaired),
+		)...)
+
+		return container, outcome, nil
 
 	case options.Reuse.RecreateOnErr:
 		err = fmt.Errorf("failed to reuseContainer: %w", err)
+		p.log().WarnContext(ctx, "failed to reuse container, recreating it",
+			containerLogAttrs(options.Name, "", options.image(), slog.Any(logKeyError, err))...)
 
-		container, recreateErr := p.recreateContainer(options)
+		outcome = RunOutcome{Origin: ContainerOriginRecreated, ReuseErr: err, RestoredFromSnapshot: restoredFromSnapshot}
+		container, recreateErr := p.recreateContainer(ctx, options, outcome)
 		if recreateErr != nil {
-			recreateErr = fmt.Errorf("failed to recreateContainer after reuseContainer err: %w", err)
-			return nil, errors.Join(err, recreateErr)
+			recreateErr = fmt.Errorf("failed to recreateContainer after reuseContainer err: %w", recreateErr)
+			return nil, RunOutcome{}, errors.Join(err, recreateErr)
 		}
 
-		return container, nil
+		return container, outcome, nil
 
 	default:
-		return nil, fmt.Errorf("failed to reuseContainer: %w", err)
+		return nil, RunOutcome{}, fmt.Errorf("failed to reuseContainer: %w", err)
 	}
 }
 
+// reuseContainer - returns existing container, repaired is true if it wasn't ready and was repaired by repairForReuse.
 func (p Pool) reuseContainer(
 	ctx context.Context, options RunOptions,
-) (container *dockertest.Resource, err error) {
+) (container *dockertest.Resource, repaired bool, err error) {
 	try := func() (container *dockertest.Resource, err error) {
-		var ok bool
-		container, ok = p.Pool.ContainerByName(fmt.Sprintf("^%s$", options.Name))
-		if !ok {
-			return nil, backoff.Permanent(fmt.Errorf("failed to p.ContainerByName `%s`: %w", options.Name, err))
+		container, ok, err := p.containerByName(ctx, options.Name)
+		if err != nil {
+			return nil, backoff.Permanent(fmt.Errorf("failed to containerByName: %w", err))
+		} else if !ok {
+			return nil, backoff.Permanent(fmt.Errorf("failed to containerByName `%s`: not found", options.Name))
 		}
 
 		err = checkContainerState(container.Container)
@@ -152,26 +748,33 @@ func (p Pool) reuseContainer(
 
 	container, err = try()
 	if err == nil {
-		return container, nil
+		return container, false, nil
 	} else if errors.As(err, ptr((*backoff.PermanentError)(nil))) {
-		return nil, err
+		return nil, false, err
 	}
 
-	err = repairForReuse(p.Pool.Client, container.Container)
+	err = p.repairForReuse(ctx, container.Container)
 	if err != nil {
-		return nil, fmt.Errorf("failed to repairForReuse: %w", err)
+		return nil, false, fmt.Errorf("failed to repairForReuse: %w", err)
 	}
 
 	container, err = backoff.Retry(ctx, try, backoff.WithBackOff(options.Reuse.Backoff))
 	if err != nil {
-		return nil, fmt.Errorf("failed to retry after repairForReuse: %w", err)
+		return nil, false, fmt.Errorf("failed to retry after repairForReuse: %w", err)
 	}
 
-	return container, nil
+	return container, true, nil
 }
 
 // repairForReuse - do something to fix container state, do nothing if container is ok.
-func repairForReuse(client *docker.Client, container *docker.Container) (err error) {
+func (p Pool) repairForReuse(ctx context.Context, container *docker.Container) (err error) {
+	logRepair := func(action string) {
+		p.log().InfoContext(ctx, "repairing container for reuse", containerLogAttrs(
+			container.Name, container.ID, containerImage(container),
+			slog.String("state", container.State.StateString()), slog.String("action", action),
+		)...)
+	}
+
 	switch {
 	case checkContainerState(container) == nil:
 		return nil
@@ -180,15 +783,17 @@ func repairForReuse(client *docker.Client, container *docker.Container) (err err
 		return nil
 
 	case container.State.Paused:
-		err = client.UnpauseContainer(container.ID)
+		logRepair("unpause")
+		err = p.unpauseContainer(ctx, container.ID)
 		if err != nil {
-			return fmt.Errorf("failed to UnpauseContainer: %w", err)
+			return fmt.Errorf("failed to unpauseContainer: %w", err)
 		}
 
 	case container.State.Status == "exited":
-		err = client.StartContainer(container.ID, container.HostConfig)
+		logRepair("start")
+		err = p.startContainer(ctx, container.ID)
 		if err != nil {
-			return fmt.Errorf("failed to StartContainer on `exited` status: %w", err)
+			return fmt.Errorf("failed to startContainer on `exited` status: %w", err)
 		}
 
 	case container.State.OOMKilled, container.State.Dead, container.State.RemovalInProgress:
@@ -225,17 +830,121 @@ func checkContainerState(container *docker.Container) (err error) {
 }
 
 func (p Pool) recreateContainer(
-	options RunOptions,
+	ctx context.Context, options RunOptions, outcome RunOutcome,
 ) (container *dockertest.Resource, err error) {
-	err = p.Pool.RemoveContainerByName(fmt.Sprintf("^%s$", options.Name))
+	err = p.removeContainerByName(ctx, options.Name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to p.RemoveContainerByName: %w", err)
+		return nil, fmt.Errorf("failed to removeContainerByName: %w", err)
 	}
 
-	container, err = p.createAndStartContainer(options)
+	container, err = p.createAndStartContainer(ctx, options, outcome)
 	if err != nil {
 		return nil, fmt.Errorf("failed to createAndStartContainer: %w", err)
 	}
 
 	return container, nil
 }
+
+// removeContainer - force removes the container with its anonymous volumes.
+func (p Pool) removeContainer(ctx context.Context, containerID string) (err error) {
+	err = p.Pool.Client.RemoveContainer(docker.RemoveContainerOptions{
+		ID:            containerID,
+		RemoveVolumes: true,
+		Force:         true,
+		Context:       ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to RemoveContainer: %w", err)
+	}
+
+	return nil
+}
+
+// removeContainerByName - force removes the container with exact name, does nothing if there is no such container.
+func (p Pool) removeContainerByName(ctx context.Context, name string) (err error) {
+	containers, err := p.Pool.Client.ListContainers(docker.ListContainersOptions{ //nolint:exhaustruct
+		All:     true,
+		Filters: map[string][]string{"name": {fmt.Sprintf("^%s$", name)}},
+		Context: ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ListContainers: %w", err)
+	}
+
+	for _, container := range containers {
+		err = p.removeContainer(ctx, container.ID)
+		if err != nil && !errors.As(err, ptr((*docker.NoSuchContainer)(nil))) {
+			return fmt.Errorf("failed to removeContainer `%s`: %w", name, err)
+		}
+		p.log().InfoContext(ctx, "container removed", containerLogAttrs(name, container.ID, container.Image)...)
+	}
+
+	return nil
+}
+
+// purgeContainer - removes container that failed to start, works even if ctx is already canceled.
+func (p Pool) purgeContainer(ctx context.Context, containerID string) {
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	_ = p.removeContainer(ctx, containerID)
+}
+
+// purgeContainerByName - like purgeContainer, but finds the container by name.
+func (p Pool) purgeContainerByName(ctx context.Context, name string) {
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	_ = p.removeContainerByName(ctx, name)
+}
+
+// newVolumes - returns names of the named volumes of the container that don't exist yet,
+// so they are created by docker with the container (see WithVolume).
+func (p Pool) newVolumes(ctx context.Context, options RunOptions) (volumes []string, err error) {
+	for _, mount := range options.HostConfig.Mounts {
+		if mount.Type != mountTypeVolume || mount.Source == "" {
+			continue
+		}
+
+		// name filter matches substring of the name
+		existingVolumes, err := p.Pool.Client.ListVolumes(docker.ListVolumesOptions{
+			Filters: map[string][]string{"name": {mount.Source}},
+			Context: ctx,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to ListVolumes: %w", err)
+		}
+
+		if !slices.ContainsFunc(existingVolumes, func(volume docker.Volume) bool { return volume.Name == mount.Source }) {
+			volumes = append(volumes, mount.Source)
+		}
+	}
+
+	return volumes, nil
+}
+
+// removeVolumes - removes the volumes, skips volumes that are already removed or used by other containers.
+func (p Pool) removeVolumes(ctx context.Context, volumes []string) (err error) {
+	for _, volume := range volumes {
+		removeErr := p.Pool.Client.RemoveVolumeWithOptions(docker.RemoveVolumeOptions{
+			Context: ctx,
+			Name:    volume,
+			Force:   false,
+		})
+		if removeErr != nil && !errors.Is(removeErr, docker.ErrNoSuchVolume) && !errors.Is(removeErr, docker.ErrVolumeInUse) {
+			err = errors.Join(err, fmt.Errorf("failed to RemoveVolumeWithOptions `%s`: %w", volume, removeErr))
+		} else if removeErr == nil {
+			p.log().InfoContext(ctx, "volume removed", slog.String("volume", volume))
+		}
+	}
+
+	return err
+}
+
+// purgeVolumes - removes volumes of the container that failed to be created, works even if ctx is already canceled.
+func (p Pool) purgeVolumes(ctx context.Context, volumes []string) {
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	_ = p.removeVolumes(ctx, volumes)
+}
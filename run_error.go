@@ -0,0 +1,126 @@
+package tcontainer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// runErrorLogsTail - number of the last log lines of the failed container saved to RunError.Logs.
+const runErrorLogsTail = 50
+
+// RunError - error returned by Pool.Run when the container fails to start or get ready.
+//   - Use errors.As(err, &runErr) to get details, errors.Is works for the wrapped errors
+//     (e.g. ErrPortInUse, ErrImageNotFound, ErrReadinessFailed, ErrStartupTimeout).
+//
+// Example usage:
+//
+//	var runErr *tcontainer.RunError
+//	if errors.As(err, &runErr) {
+//		t.Logf("container %s failed in %s phase, logs:\n%s", runErr.Name, runErr.Phase, runErr.Logs)
+//	}
+type RunError struct {
+	// Phase - startup phase in which Run failed.
+	Phase StartupPhase
+	// ContainerID - ID of the failed container, empty if it failed before start (the container is removed anyway).
+	ContainerID string
+	// Name - name of the container (see WithContainerName()), may be empty.
+	Name string
+	// Image - image of the container (repository:tag).
+	Image string
+	// Logs - last lines of the container logs (stdout and stderr), collected before removal of the started container.
+	Logs string
+	// Err - cause of the failure.
+	Err error
+}
+
+func (e *RunError) Error() string {
+	target := fmt.Sprintf("`%s`", e.Image)
+	if e.Name != "" {
+		target = fmt.Sprintf("`%s` (%s)", e.Name, e.Image)
+	}
+
+	return fmt.Sprintf("failed to run container %s in `%s` phase: %s", target, e.Phase, e.Err)
+}
+
+func (e *RunError) Unwrap() error {
+	return e.Err
+}
+
+// phaseError - marks the error with the phase in which it occurred (see runStartupPhase), doesn't change the message.
+type phaseError struct {
+	phase StartupPhase
+	err   error
+}
+
+func (e *phaseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *phaseError) Unwrap() error {
+	return e.err
+}
+
+// newRunError - returns *RunError for err of the run, phase is used if err doesn't belong to a specific phase.
+//   - Collects logs of the started container before its cleanup.
+func (p Pool) newRunError(
+	ctx context.Context, phase StartupPhase, options RunOptions, container *Container, err error,
+) *RunError {
+	var phaseErr *phaseError
+	if errors.As(err, &phaseErr) {
+		phase = phaseErr.phase
+	}
+
+	runErr := &RunError{
+		Phase:       phase,
+		ContainerID: "",
+		Name:        options.Name,
+		Image:       options.image(),
+		Logs:        "",
+		Err:         err,
+	}
+
+	if container != nil {
+		runErr.ContainerID = container.Container.ID
+		runErr.Logs = p.tailLogs(ctx, container)
+	}
+
+	return runErr
+}
+
+// tailLogs - returns last lines of the container logs, works even if ctx is already canceled.
+func (p Pool) tailLogs(ctx context.Context, container *Container) (logs string) {
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	buf := &bytes.Buffer{}
+	_ = p.Pool.Client.Logs(docker.LogsOptions{ //nolint:exhaustruct
+		Context:      ctx,
+		Container:    container.Container.ID,
+		OutputStream: buf,
+		ErrorStream:  buf,
+		Stdout:       true,
+		Stderr:       true,
+		Tail:         fmt.Sprint(runErrorLogsTail),
+		RawTerminal:  container.Container.Config != nil && container.Container.Config.Tty,
+	})
+
+	return buf.String()
+}
+
+// isImageNotFoundErr - checks that the pull error is caused by missing image (or repository) in the registry.
+func isImageNotFoundErr(err error) bool {
+	if errors.Is(err, docker.ErrNoSuchImage) {
+		return true
+	}
+
+	msg := err.Error()
+
+	return strings.Contains(msg, "manifest unknown") ||
+		strings.Contains(msg, "repository does not exist") ||
+		strings.Contains(msg, "pull access denied")
+}
@@ -1,10 +1,16 @@
 package tcontainer
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +33,24 @@ const (
 	defaultReuseBackoffMaxInterval     = time.Second
 
 	defaultRetryBackoffMaxInterval = time.Second * 5
+
+	defaultPortInUseMaxTries               = 5
+	defaultPortInUseBackoffInitialInterval = time.Millisecond * 200
+	defaultPortInUseBackoffMaxInterval     = time.Second * 2
+
+	networkModeHost            = "host"
+	networkModeNone            = "none"
+	networkModeContainerPrefix = "container:"
+
+	mountTypeBind   = "bind"
+	mountTypeVolume = "volume"
+	mountTypeTmpfs  = "tmpfs"
+
+	// expirySignal - stop signal of the expiry, processes ignore it so docker kills the container
+	// after the stop timeout (see ContainerExpiry). Stop signal of the image is kept for (Pool).Stop.
+	expirySignal = "SIGWINCH"
+	// expiryAPIVersion - the first docker API version with `signal` parameter of the container stop.
+	expiryAPIVersion = "1.42"
 )
 
 var (
@@ -36,6 +60,7 @@ var (
 	ErrOptionConflict = errors.New("conflicted options")
 
 	containerNameInvalidCharsRegexp = regexp.MustCompile("[^a-zA-Z0-9_.-]")
+	containerNameRegexp             = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9_.-]*$")
 )
 
 type (
@@ -51,12 +76,14 @@ type (
 		ExposedPorts []string
 		WorkingDir   string
 		Networks     []*dockertest.Network // optional networks to join
-		Labels       map[string]string
-		Auth         docker.AuthConfiguration
-		User         string
-		Tty          bool
-		Platform     string
-		HostConfig   docker.HostConfig
+		// optional DNS aliases of the container by network ID (see WithNetwork)
+		NetworkAliases map[string][]string
+		Labels         map[string]string
+		Auth           docker.AuthConfiguration
+		User           string
+		Tty            bool
+		Platform       string
+		HostConfig     docker.HostConfig
 
 		// Allows you to reuse a container instead of getting an error that the container already exists.
 		// See [RetryOptions] struct description
@@ -72,6 +99,93 @@ type (
 		//
 		// Default: `false`
 		RemoveOnExists bool
+
+		// Retry container creation if host port is already in use.
+		// See [PortInUseOptions] struct description.
+		PortInUse PortInUseOptions
+
+		// Functions that run at specific phases of container lifecycle.
+		// See [Hooks] struct description.
+		Hooks Hooks
+
+		// Run container from the snapshot image if it exists.
+		// See [RestoreSnapshotOptions] struct description.
+		RestoreSnapshot RestoreSnapshotOptions
+
+		// Max duration of the container startup: image pull, creation, start and readiness check including hooks.
+		//	- `Run` function returns [StartupTimeoutError] (wraps `ErrStartupTimeout`) with the interrupted phase.
+		//
+		// Default: `0` - no limit (readiness check is still limited by `PoolOptions.MaxWait` if set)
+		StartupTimeout time.Duration
+
+		// Max duration of each startup phase (see [StartupPhase]), phases without timeout aren't limited.
+		//	- `Run` function returns [StartupTimeoutError] (wraps `ErrStartupTimeout`) with the phase.
+		//
+		// Default: `nil`
+		PhaseTimeouts map[StartupPhase]time.Duration
+	}
+
+	// Allows you to run container from the snapshot image (see (Pool).Snapshot) with prepared data.
+	//	- If snapshot image `Name` exists - container runs from it instead of `Repository:Tag`.
+	//	- Otherwise container runs from `Repository:Tag`, then `Setup` runs as the first `AfterReady` hook
+	//		and the snapshot is created, so next runs restore it.
+	//	- `SnapshotOptions` are used to create the snapshot after `Setup` (e.g. WithBeforeSnapshot).
+	//	- `Setup` doesn't run for reused containers.
+	//	- Reused container may run from the snapshot image or from `Repository:Tag`,
+	//		default `ContainerConfigCheck` accepts both images.
+	//	- Use `(*Container).Outcome().RestoredFromSnapshot` to check which way was used.
+	//
+	// # Default:
+	//	- `Name` - empty, snapshots are not used
+	RestoreSnapshotOptions struct {
+		Name            string
+		Setup           Hook
+		SnapshotOptions []SnapshotOption
+
+		// baseImage - `Repository:Tag` before it was replaced by the snapshot image.
+		baseImage string
+	}
+
+	// Hooks - functions that run at specific phases of container lifecycle (see WithHooks).
+	//	- Hooks of each phase run in order they were added, the first error stops the phase.
+	//	- Error of any hook after container creation purges the container
+	//		(the same as failed `Retry.Operation`) and `Run` returns the error.
+	//
+	// # Phases:
+	//	- `BeforeCreate` - can mutate final options (options are validated again after hooks).
+	//		Runs for reused containers too, so options used for reuse checks are the same.
+	//	- `AfterCreate` - container is created but not started (e.g. copy files, connect networks).
+	//		Runs only for created (and recreated) containers.
+	//	- `AfterStart` - container is started (created, recreated or reused), readiness is not checked yet.
+	//	- `AfterReady` - `Retry.Operation` succeeded.
+	//	- `BeforeTerminate` - before container is removed (e.g. dump DB), see (*Container).Terminate.
+	//	- `AfterTerminate` - after container is removed, see (*Container).Terminate.
+	Hooks struct {
+		BeforeCreate    []BeforeCreateHook
+		AfterCreate     []Hook
+		AfterStart      []Hook
+		AfterReady      []Hook
+		BeforeTerminate []Hook
+		AfterTerminate  []Hook
+	}
+
+	// BeforeCreateHook - runs before container creation and can mutate final options.
+	BeforeCreateHook func(ctx context.Context, options *RunOptions) (err error)
+
+	// Hook - runs at specific phase of container lifecycle.
+	Hook func(ctx context.Context, container *Container) (err error)
+
+	// Allows you to retry container creation when fixed host port is already in use
+	// (e.g. by container of previous test that is still being removed).
+	//	- `Run` function returns error that wraps `ErrPortInUse` if all tries have failed.
+	//	- `MaxTries` - total number of tries, `0` or `1` - don't retry.
+	//	- `Backoff` - interval between tries.
+	//
+	// # Default:
+	//	- `MaxTries` - `0`, `WithFixedHostPort` sets `5` if it's not set
+	PortInUseOptions struct {
+		MaxTries uint
+		Backoff  backoff.BackOff
 	}
 
 	// Allows you to reuse a container instead of getting an error that the container already exists.
@@ -86,7 +200,7 @@ type (
 	// # Default:
 	//	- `Reuse` - `false`
 	//	- `RecreateOnErr` - `false`
-	//	- `ConfigChecks` - checks that old container have the same image, exposed ports and port bindings
+	//	- `ConfigChecks` - checks that old container have the same image, exposed ports, port bindings and mounts
 	//
 	// # Example
 	//	func(options *RunOptions) (err error) {
@@ -126,7 +240,7 @@ type (
 	//
 	// # Example:
 	//	func(options *RunOptions) (err error) {
-	//	    options.Retry.Operation = func(ctx context.Context, container *dockertest.Resource) (err error) {
+	//	    options.Retry.Operation = func(ctx context.Context, container *Container) (err error) {
 	//	        fmt.Println("ping")
 	//	        return nil
 	//	    }
@@ -155,24 +269,520 @@ type (
 //	WithContainerName(t.Name(), "redis") // "Test/with/invalid/chars", "redis" -> "Test-with-invalid-chars-redis"
 func WithContainerName(nameParts ...string) RunOption {
 	return func(options *RunOptions) (err error) {
-		const delimiter = "-"
+		options.Name = formatContainerName(nameParts...)
+		return nil
+	}
+}
+
+// formatContainerName - see [WithContainerName].
+func formatContainerName(nameParts ...string) (name string) {
+	const delimiter = "-"
+
+	// remove empty parts
+	nameParts = slices.DeleteFunc(nameParts, func(s string) bool { return s == "" })
+
+	// join parts
+	name = strings.Join(nameParts, delimiter)
+
+	// replace invalid chars
+	name = containerNameInvalidCharsRegexp.ReplaceAllString(name, delimiter)
+
+	// replace delimiter duplications
+	for strings.Contains(name, delimiter+delimiter) {
+		name = strings.ReplaceAll(name, delimiter+delimiter, delimiter)
+	}
+
+	return name
+}
+
+// WithTag - use custom image tag instead of "latest".
+func WithTag(tag string) RunOption {
+	return func(options *RunOptions) (err error) {
+		if tag == "" {
+			return fmt.Errorf("%w: tag is required", ErrInvalidOptions)
+		}
+
+		// set option
+		options.Tag = tag
+
+		return nil
+	}
+}
+
+// WithEnv - adds environment variable to the container.
+// Rewrites value of the variable with the same name, other variables are kept.
+func WithEnv(name, value string) RunOption {
+	return func(options *RunOptions) (err error) {
+		if name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf("%w: invalid env name `%s`", ErrInvalidOptions, name)
+		}
+
+		env := name + "=" + value
+
+		idx := slices.IndexFunc(options.Env, func(e string) bool { return strings.HasPrefix(e, name+"=") })
+		if idx != -1 {
+			options.Env[idx] = env
+			return nil
+		}
+
+		options.Env = append(options.Env, env)
+
+		return nil
+	}
+}
+
+// WithEnvMap - adds environment variables to the container in the order of sorted names.
+// See [WithEnv].
+func WithEnvMap(env map[string]string) RunOption {
+	return func(options *RunOptions) (err error) {
+		for _, name := range slices.Sorted(maps.Keys(env)) {
+			err = WithEnv(name, env[name])(options)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithCmd - use custom command instead of image CMD. Rewrites previous command.
+func WithCmd(cmd ...string) RunOption {
+	return func(options *RunOptions) (err error) {
+		options.Cmd = cmd
+		return nil
+	}
+}
+
+// WithEntrypoint - use custom entrypoint instead of image ENTRYPOINT. Rewrites previous entrypoint.
+func WithEntrypoint(entrypoint ...string) RunOption {
+	return func(options *RunOptions) (err error) {
+		options.Entrypoint = entrypoint
+		return nil
+	}
+}
+
+// WithExposedPorts - adds exposed ports (e.g. "80", "53/udp"), already exposed ports are kept.
+func WithExposedPorts(ports ...PrivatePort) RunOption {
+	return func(options *RunOptions) (err error) {
+		for _, port := range ports {
+			if port == "" {
+				return fmt.Errorf("%w: exposed port is required", ErrInvalidOptions)
+			}
+			if !slices.Contains(options.ExposedPorts, port) {
+				options.ExposedPorts = append(options.ExposedPorts, port)
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithPortBinding - binds privatePort (port inside the container) to the hostPort.
+//   - privatePort will be exposed if it's not exposed yet.
+//   - Adds binding to already existed bindings of privatePort.
+//
+// Example:
+//
+//	WithPortBinding("80", "8080") // container port 80 is accessible by localhost:8080
+func WithPortBinding(privatePort PrivatePort, hostPort string) RunOption {
+	return func(options *RunOptions) (err error) {
+		if _, err = strconv.ParseUint(hostPort, 10, 16); err != nil {
+			return fmt.Errorf("%w: invalid host port `%s`", ErrInvalidOptions, hostPort)
+		}
+
+		return addPortBinding(options, privatePort, hostPort)
+	}
+}
+
+// addPortBinding - exposes privatePort and adds it's binding to hostPort ("" - random port) if it doesn't exist yet.
+func addPortBinding(options *RunOptions, privatePort PrivatePort, hostPort string) (err error) {
+	err = WithExposedPorts(privatePort)(options)
+	if err != nil {
+		return err
+	}
+
+	binding := docker.PortBinding{HostIP: "", HostPort: hostPort}
+	port := docker.Port(privatePort)
+
+	if options.HostConfig.PortBindings == nil {
+		options.HostConfig.PortBindings = make(map[docker.Port][]docker.PortBinding)
+	}
+	if !slices.Contains(options.HostConfig.PortBindings[port], binding) {
+		options.HostConfig.PortBindings[port] = append(options.HostConfig.PortBindings[port], binding)
+	}
+
+	return nil
+}
+
+// WithRandomHostPort - binds privatePort (port inside the container) to the random free host port.
+// The port is allocated by docker, so there is no race between parallel tests unlike with freeport packages.
+//   - privatePort will be exposed if it's not exposed yet.
+//   - Use GetHostEndpoints(container)[privatePort] to get allocated host port after Run.
+func WithRandomHostPort(privatePort PrivatePort) RunOption {
+	return func(options *RunOptions) (err error) {
+		return addPortBinding(options, privatePort, "")
+	}
+}
+
+// WithFixedHostPort - binds privatePort (port inside the container) to the hostPort.
+// Same as WithPortBinding but Run retries container creation if hostPort is already in use,
+// and returns error that wraps ErrPortInUse if the port is still in use.
+// See [PortInUseOptions].
+func WithFixedHostPort(privatePort PrivatePort, hostPort string) RunOption {
+	return func(options *RunOptions) (err error) {
+		err = WithPortBinding(privatePort, hostPort)(options)
+		if err != nil {
+			return err
+		}
+
+		if options.PortInUse.MaxTries == 0 {
+			options.PortInUse.MaxTries = defaultPortInUseMaxTries
+		}
+
+		return nil
+	}
+}
+
+// WithBindMount - mounts hostPath (file or directory) into the container by containerPath.
+//   - Relative hostPath is resolved relative to the current working directory.
+//   - Rewrites previous mount with the same containerPath.
+func WithBindMount(hostPath, containerPath string, readOnly bool) RunOption {
+	return func(options *RunOptions) (err error) {
+		hostPath, err = filepath.Abs(hostPath)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get absolute host path: %w", ErrInvalidOptions, err)
+		}
+		_, err = os.Stat(hostPath)
+		if err != nil {
+			return fmt.Errorf("%w: failed to stat host path: %w", ErrInvalidOptions, err)
+		}
+
+		return setMount(options, docker.HostMount{ //nolint:exhaustruct
+			Type:     mountTypeBind,
+			Source:   hostPath,
+			Target:   containerPath,
+			ReadOnly: readOnly,
+		})
+	}
+}
+
+// WithTmpfs - mounts in-memory tmpfs into the container by containerPath.
+//   - sizeBytes - size limit of the tmpfs, 0 - unlimited.
+//   - Rewrites previous mount with the same containerPath.
+func WithTmpfs(containerPath string, sizeBytes int64) RunOption {
+	return func(options *RunOptions) (err error) {
+		if sizeBytes < 0 {
+			return fmt.Errorf("%w: negative tmpfs size `%d`", ErrInvalidOptions, sizeBytes)
+		}
+
+		return setMount(options, docker.HostMount{ //nolint:exhaustruct
+			Type:          mountTypeTmpfs,
+			Target:        containerPath,
+			TempfsOptions: &docker.TempfsOptions{SizeBytes: sizeBytes, Mode: 0},
+		})
+	}
+}
+
+// WithVolume - mounts named volume into the container by containerPath.
+//   - Volume is created if it doesn't exist yet, created volume is labelled with DefaultLabelKeyValue.
+//   - Volume created by the Run is removed by container.Close() / Terminate (unless the container is run WithReuse),
+//     volume that already existed is kept, so the data could be shared between runs and containers.
+//   - Volumes of the containers removed by AutoRemove or expiry are left, use (Pool).Prune to remove them.
+//   - Rewrites previous mount with the same containerPath.
+func WithVolume(name, containerPath string) RunOption {
+	return func(options *RunOptions) (err error) {
+		if !containerNameRegexp.MatchString(name) {
+			return fmt.Errorf("%w: invalid volume name `%s`", ErrInvalidOptions, name)
+		}
+
+		return setMount(options, docker.HostMount{ //nolint:exhaustruct
+			Type:          mountTypeVolume,
+			Source:        name,
+			Target:        containerPath,
+			VolumeOptions: newVolumeOptions(),
+		})
+	}
+}
+
+// WithEphemeralVolume - mounts anonymous volume into the container by containerPath.
+//   - Volume is labelled with DefaultLabelKeyValue.
+//   - Volume is removed with the container (container.Close(), AutoRemove, Prune),
+//     but it's kept while the container is reused.
+//   - Rewrites previous mount with the same containerPath.
+func WithEphemeralVolume(containerPath string) RunOption {
+	return func(options *RunOptions) (err error) {
+		return setMount(options, docker.HostMount{ //nolint:exhaustruct
+			Type:          mountTypeVolume,
+			Target:        containerPath,
+			VolumeOptions: newVolumeOptions(),
+		})
+	}
+}
+
+// setMount - adds mount or rewrites existing mount with the same target.
+func setMount(options *RunOptions, mount docker.HostMount) (err error) {
+	if !path.IsAbs(mount.Target) {
+		return fmt.Errorf("%w: container path `%s` must be absolute", ErrInvalidOptions, mount.Target)
+	}
+
+	idx := slices.IndexFunc(options.HostConfig.Mounts, func(m docker.HostMount) bool { return m.Target == mount.Target })
+	if idx != -1 {
+		options.HostConfig.Mounts[idx] = mount
+		return nil
+	}
+
+	options.HostConfig.Mounts = append(options.HostConfig.Mounts, mount)
+
+	return nil
+}
+
+func newVolumeOptions() *docker.VolumeOptions {
+	return &docker.VolumeOptions{ //nolint:exhaustruct
+		Labels: map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue},
+	}
+}
+
+// WithNetwork - joins the container to the network (see (Pool).CreateNetwork).
+//   - aliases - DNS names of the container in the network, container name is always available.
+//   - Adds aliases to already existing aliases if the network was already added.
+func WithNetwork(network *dockertest.Network, aliases ...string) RunOption {
+	return func(options *RunOptions) (err error) {
+		if network == nil || network.Network == nil {
+			return fmt.Errorf("%w: network is nil", ErrInvalidOptions)
+		}
 
-		// remove empty parts
-		nameParts = slices.DeleteFunc(nameParts, func(s string) bool { return s == "" })
+		isJoined := func(joined *dockertest.Network) bool { return joined.Network.ID == network.Network.ID }
+		if !slices.ContainsFunc(options.Networks, isJoined) {
+			options.Networks = append(options.Networks, network)
+		}
 
-		// join parts
-		name := strings.Join(nameParts, delimiter)
+		if len(aliases) == 0 {
+			return nil
+		}
 
-		// replace invalid chars
-		name = containerNameInvalidCharsRegexp.ReplaceAllString(name, delimiter)
+		if options.NetworkAliases == nil {
+			options.NetworkAliases = make(map[string][]string, 1)
+		}
+		for _, alias := range aliases {
+			if !containerNameRegexp.MatchString(alias) {
+				return fmt.Errorf("%w: invalid network alias `%s`", ErrInvalidOptions, alias)
+			}
+			if !slices.Contains(options.NetworkAliases[network.Network.ID], alias) {
+				options.NetworkAliases[network.Network.ID] = append(options.NetworkAliases[network.Network.ID], alias)
+			}
+		}
+
+		return nil
+	}
+}
 
-		// replace delimiter duplications
-		for strings.Contains(name, delimiter+delimiter) {
-			name = strings.ReplaceAll(name, delimiter+delimiter, delimiter)
+// WithNetworkMode - use network mode, e.g.
+//   - "bridge" - default docker network.
+//   - "host" - use host network stack, port bindings are ignored.
+//   - "none" - no networking.
+//   - "container:<name|id>" - use network stack of other container.
+//
+// Modes "host", "none" and "container:<name|id>" should not be used together with WithNetwork
+// (will return `ErrOptionConflict` error).
+func WithNetworkMode(mode string) RunOption {
+	return func(options *RunOptions) (err error) {
+		if mode == "" || mode == networkModeContainerPrefix {
+			return fmt.Errorf("%w: invalid network mode `%s`", ErrInvalidOptions, mode)
 		}
 
 		// set option
-		options.Name = name
+		options.HostConfig.NetworkMode = mode
+
+		return nil
+	}
+}
+
+// WithContainerLabels - adds labels to the container.
+// Rewrites labels with the same keys, other labels (like DefaultLabelKeyValue) are kept.
+func WithContainerLabels(labels map[string]string) RunOption {
+	return func(options *RunOptions) (err error) {
+		if _, ok := labels[""]; ok {
+			return fmt.Errorf("%w: label key is required", ErrInvalidOptions)
+		}
+
+		if options.Labels == nil {
+			options.Labels = make(map[string]string, len(labels))
+		}
+		maps.Copy(options.Labels, labels)
+
+		return nil
+	}
+}
+
+// WithUser - run container processes as user (e.g. "nobody", "1000:1000").
+func WithUser(user string) RunOption {
+	return func(options *RunOptions) (err error) {
+		options.User = user
+		return nil
+	}
+}
+
+// WithWorkingDir - use custom working directory inside the container.
+func WithWorkingDir(dir string) RunOption {
+	return func(options *RunOptions) (err error) {
+		options.WorkingDir = dir
+		return nil
+	}
+}
+
+// WithReuse - reuse container if it already exists.
+//   - recreateOnErr - recreate container if it's impossible to reuse it.
+//
+// See [ReuseContainerOptions].
+func WithReuse(recreateOnErr bool) RunOption {
+	return func(options *RunOptions) (err error) {
+		options.Reuse.Reuse = true
+		options.Reuse.RecreateOnErr = recreateOnErr
+
+		return nil
+	}
+}
+
+// WithRemoveOnExists - remove existing container with the same name instead of getting ErrContainerAlreadyExists.
+// Should not be used together with WithReuse - will return `ErrOptionConflict` error.
+func WithRemoveOnExists() RunOption {
+	return func(options *RunOptions) (err error) {
+		options.RemoveOnExists = true
+		return nil
+	}
+}
+
+// WithExpiry - container will be removed after expiry. Use 0 to disable expiry.
+//   - Expiry requires docker API 1.42+ (Docker 23+), it's logged as failed by older daemons.
+func WithExpiry(expiry time.Duration) RunOption {
+	return func(options *RunOptions) (err error) {
+		if expiry < 0 {
+			return fmt.Errorf("%w: negative expiry `%s`", ErrInvalidOptions, expiry)
+		}
+
+		// set option
+		options.ContainerExpiry = expiry
+
+		return nil
+	}
+}
+
+// WithRetry - wait until operation succeeds after container start.
+//   - retryBackoff is optional, default backoff is used if it's nil.
+//   - retryBackoff is copied for each Run if it's *backoff.ExponentialBackOff or *backoff.ConstantBackOff,
+//     so the option can be shared (e.g. by WithDefaults), other implementations must be safe for concurrent use.
+//
+// See [RetryOptions].
+func WithRetry(operation RetryOperation, retryBackoff backoff.BackOff) RunOption {
+	return func(options *RunOptions) (err error) {
+		if operation == nil {
+			return fmt.Errorf("%w: retry operation is nil", ErrInvalidOptions)
+		}
+
+		// set option
+		options.Retry.Operation = operation
+		if retryBackoff != nil {
+			options.Retry.Backoff = retryBackoff
+		}
+
+		return nil
+	}
+}
+
+// WithHostConfig - modify HostConfig. Previous values are passed to modify function.
+//
+// Example:
+//
+//	WithHostConfig(func(hostConfig *docker.HostConfig) {
+//		hostConfig.AutoRemove = false
+//		hostConfig.CapAdd = append(hostConfig.CapAdd, "NET_ADMIN")
+//	})
+func WithHostConfig(modify func(hostConfig *docker.HostConfig)) RunOption {
+	return func(options *RunOptions) (err error) {
+		if modify == nil {
+			return fmt.Errorf("%w: HostConfig modify function is nil", ErrInvalidOptions)
+		}
+
+		modify(&options.HostConfig)
+
+		return nil
+	}
+}
+
+// WithHooks - add hooks for container lifecycle phases (see Hooks).
+// Hooks are appended to the hooks added by previous options.
+func WithHooks(hooks Hooks) RunOption {
+	return func(options *RunOptions) (err error) {
+		options.Hooks.BeforeCreate = append(options.Hooks.BeforeCreate, hooks.BeforeCreate...)
+		options.Hooks.AfterCreate = append(options.Hooks.AfterCreate, hooks.AfterCreate...)
+		options.Hooks.AfterStart = append(options.Hooks.AfterStart, hooks.AfterStart...)
+		options.Hooks.AfterReady = append(options.Hooks.AfterReady, hooks.AfterReady...)
+		options.Hooks.BeforeTerminate = append(options.Hooks.BeforeTerminate, hooks.BeforeTerminate...)
+		options.Hooks.AfterTerminate = append(options.Hooks.AfterTerminate, hooks.AfterTerminate...)
+
+		return nil
+	}
+}
+
+// WithRestoreFromSnapshot - run container from the snapshot image (see (Pool).Snapshot) if it exists,
+// otherwise run it from the base image, prepare data by setup and create the snapshot with snapshotOpts.
+// See [RestoreSnapshotOptions] struct description.
+func WithRestoreFromSnapshot(name string, setup Hook, snapshotOpts ...SnapshotOption) RunOption {
+	return func(options *RunOptions) (err error) {
+		if setup == nil {
+			return fmt.Errorf("%w: snapshot setup is nil", ErrInvalidOptions)
+		}
+
+		options.RestoreSnapshot = RestoreSnapshotOptions{
+			Name:            name,
+			Setup:           setup,
+			SnapshotOptions: snapshotOpts,
+			baseImage:       "",
+		}
+
+		return nil
+	}
+}
+
+// WithStartupTimeout - max duration of the container startup (pull, create, start and readiness check).
+// See [RunOptions].
+//
+// Example usage:
+//
+//	WithStartupTimeout(time.Minute) // the container must be ready in 1m
+func WithStartupTimeout(timeout time.Duration) RunOption {
+	return func(options *RunOptions) (err error) {
+		if timeout < 0 {
+			return fmt.Errorf("%w: startup timeout can't be negative", ErrInvalidOptions)
+		}
+
+		options.StartupTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithPhaseTimeout - max duration of the startup phase. Each phase has its own timeout.
+// See [RunOptions].
+//
+// Example usage:
+//
+//	WithPhaseTimeout(StartupPhasePull, time.Minute*5), WithPhaseTimeout(StartupPhaseReady, time.Second*30)
+func WithPhaseTimeout(phase StartupPhase, timeout time.Duration) RunOption {
+	return func(options *RunOptions) (err error) {
+		err = phase.validate()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidOptions, err)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("%w: `%s` phase timeout can't be negative", ErrInvalidOptions, phase)
+		}
+
+		if options.PhaseTimeouts == nil {
+			options.PhaseTimeouts = make(map[StartupPhase]time.Duration, 1)
+		}
+		options.PhaseTimeouts[phase] = timeout
 
 		return nil
 	}
@@ -184,6 +794,11 @@ func WithContainerName(nameParts ...string) RunOption {
 // Each option rewrites previous value
 //
 //	ApplyRunOptions(WithContainerName("first"), WithContainerName("second")) // "second"
+//
+// Except options that accumulate values - WithEnv, WithEnvMap, WithExposedPorts, WithPortBinding,
+// WithContainerLabels, WithHooks, WithPhaseTimeout
+//
+//	ApplyRunOptions(WithEnv("A", "1"), WithEnv("B", "2"), WithEnv("A", "3")) // A=3, B=2
 func ApplyRunOptions(repository string, customOpts ...RunOption) (
 	options RunOptions, err error,
 ) {
@@ -196,8 +811,10 @@ func ApplyRunOptions(repository string, customOpts ...RunOption) (
 		}
 	}
 
-	options.Retry.Backoff.Reset()
-	options.Reuse.Backoff.Reset()
+	// backoffs are stateful - the ones shared by options (e.g. WithDefaults of the Pool) are copied for each Run
+	options.Retry.Backoff = cloneBackOff(options.Retry.Backoff)
+	options.Reuse.Backoff = cloneBackOff(options.Reuse.Backoff)
+	options.PortInUse.Backoff = cloneBackOff(options.PortInUse.Backoff)
 
 	err = options.validate()
 	if err != nil {
@@ -217,22 +834,28 @@ func (o RunOptions) getDefault(repository string) (defaultRunOptions RunOptions)
 	reuseBackoff.MaxInterval = defaultReuseBackoffMaxInterval
 	reuseBackoff.Reset()
 
+	portInUseBackoff := backoff.NewExponentialBackOff()
+	portInUseBackoff.InitialInterval = defaultPortInUseBackoffInitialInterval
+	portInUseBackoff.MaxInterval = defaultPortInUseBackoffMaxInterval
+	portInUseBackoff.Reset()
+
 	return RunOptions{
-		Hostname:     "",
-		Name:         "",
-		Repository:   repository,
-		Tag:          defaultImageTag,
-		Env:          nil,
-		Entrypoint:   nil,
-		Cmd:          nil,
-		ExposedPorts: nil,
-		WorkingDir:   "",
-		Networks:     nil,
-		Labels:       map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue},
-		Auth:         docker.AuthConfiguration{}, //nolint:exhaustruct
-		User:         "",
-		Tty:          false,
-		Platform:     "",
+		Hostname:       "",
+		Name:           "",
+		Repository:     repository,
+		Tag:            defaultImageTag,
+		Env:            nil,
+		Entrypoint:     nil,
+		Cmd:            nil,
+		ExposedPorts:   nil,
+		WorkingDir:     "",
+		Networks:       nil,
+		NetworkAliases: nil,
+		Labels:         map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue},
+		Auth:           docker.AuthConfiguration{}, //nolint:exhaustruct
+		User:           "",
+		Tty:            false,
+		Platform:       "",
 		HostConfig: docker.HostConfig{ //nolint:exhaustruct
 			AutoRemove: defaultAutoremoveContainer,
 		},
@@ -250,13 +873,33 @@ func (o RunOptions) getDefault(repository string) (defaultRunOptions RunOptions)
 			},
 		},
 		RemoveOnExists: defaultRemoveContainerOnExists,
+		PortInUse: PortInUseOptions{
+			MaxTries: 0,
+			Backoff:  portInUseBackoff,
+		},
+		Hooks: Hooks{
+			BeforeCreate:    nil,
+			AfterCreate:     nil,
+			AfterStart:      nil,
+			AfterReady:      nil,
+			BeforeTerminate: nil,
+			AfterTerminate:  nil,
+		},
+		RestoreSnapshot: RestoreSnapshotOptions{
+			Name:            "",
+			Setup:           nil,
+			SnapshotOptions: nil,
+			baseImage:       "",
+		},
+		StartupTimeout: 0,
+		PhaseTimeouts:  nil,
 	}
 }
 
 func defaultContainerConfigCheck(container *docker.Container, expectedOptions RunOptions) (err error) {
 	// image check
 	expectImage := expectedOptions.Repository + ":" + expectedOptions.Tag
-	if container.Config.Image != expectImage {
+	if container.Config.Image != expectImage && !isSnapshotImage(container.Config.Image, expectedOptions) {
 		return fmt.Errorf(
 			"other image - `%s` (old) instead of `%s` (new)",
 			container.Config.Image, expectImage,
@@ -279,6 +922,18 @@ func defaultContainerConfigCheck(container *docker.Container, expectedOptions Ru
 		return fmt.Errorf("failed to checkPortBindings: %w", err)
 	}
 
+	// mounts check
+	err = checkMounts(expectedOptions.HostConfig.Mounts, container.HostConfig.Mounts)
+	if err != nil {
+		return fmt.Errorf("failed to checkMounts: %w", err)
+	}
+
+	// networks check
+	err = checkNetworks(expectedOptions, container)
+	if err != nil {
+		return fmt.Errorf("failed to checkNetworks: %w", err)
+	}
+
 	// [skip env check] // differences can be valid
 	// [skip cmd check] // expectedOptions can have empty cmd // differences can be valid?
 
@@ -314,6 +969,53 @@ func checkPortBindings(expected, actual map[docker.Port][]docker.PortBinding) (e
 	return nil
 }
 
+func checkMounts(expected, actual []docker.HostMount) (err error) {
+	for _, expectedMount := range expected {
+		found := slices.ContainsFunc(actual, func(actualMount docker.HostMount) bool {
+			return actualMount.Type == expectedMount.Type &&
+				actualMount.Target == expectedMount.Target &&
+				actualMount.Source == expectedMount.Source &&
+				actualMount.ReadOnly == expectedMount.ReadOnly
+		})
+		if !found {
+			return fmt.Errorf(
+				"%w: not found %s mount `%s` to `%s`",
+				ErrReuseContainerConflict, expectedMount.Type, expectedMount.Source, expectedMount.Target,
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkNetworks - checks that container is connected to the same networks (e.g. network wasn't recreated) with aliases.
+func checkNetworks(expectedOptions RunOptions, container *docker.Container) (err error) {
+	networkMode := expectedOptions.HostConfig.NetworkMode
+	if networkMode != "" && networkMode != container.HostConfig.NetworkMode {
+		return fmt.Errorf(
+			"%w: other network mode - `%s` (old) instead of `%s` (new)",
+			ErrReuseContainerConflict, container.HostConfig.NetworkMode, networkMode,
+		)
+	}
+
+	for _, network := range expectedOptions.Networks {
+		containerNetwork, ok := container.NetworkSettings.Networks[network.Network.Name]
+		if !ok || containerNetwork.NetworkID != network.Network.ID {
+			return fmt.Errorf("%w: not connected to network `%s`", ErrReuseContainerConflict, network.Network.Name)
+		}
+
+		for _, alias := range expectedOptions.NetworkAliases[network.Network.ID] {
+			if !slices.Contains(containerNetwork.Aliases, alias) {
+				return fmt.Errorf(
+					"%w: not found alias `%s` in network `%s`", ErrReuseContainerConflict, alias, network.Network.Name,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (o RunOptions) validate() (err error) {
 	if o.Repository == "" {
 		return fmt.Errorf("%w: repository is required", ErrInvalidOptions)
@@ -323,34 +1025,134 @@ func (o RunOptions) validate() (err error) {
 		return fmt.Errorf("%w: RemoveOnExists conflicts with Reuse", ErrOptionConflict)
 	}
 
+	networkMode := o.HostConfig.NetworkMode
+	isolatedNetworkMode := networkMode == networkModeHost || networkMode == networkModeNone ||
+		strings.HasPrefix(networkMode, networkModeContainerPrefix)
+	if isolatedNetworkMode && len(o.Networks) > 0 {
+		return fmt.Errorf("%w: network mode `%s` conflicts with Networks", ErrOptionConflict, networkMode)
+	}
+	if strings.HasPrefix(networkMode, networkModeContainerPrefix) && len(o.HostConfig.PortBindings) > 0 {
+		return fmt.Errorf("%w: network mode `%s` conflicts with PortBindings", ErrOptionConflict, networkMode)
+	}
+
+	if o.PortInUse.MaxTries > 1 && o.PortInUse.Backoff == nil {
+		return fmt.Errorf("%w: PortInUse.Backoff is required when PortInUse.MaxTries is set", ErrInvalidOptions)
+	}
+
+	err = o.Hooks.validate()
+	if err != nil {
+		return fmt.Errorf("failed to Hooks.validate: %w", err)
+	}
+
+	if o.RestoreSnapshot.Name != "" {
+		_, _, err = parseImageRef(o.RestoreSnapshot.Name)
+		if err != nil {
+			return fmt.Errorf("invalid RestoreSnapshot.Name: %w", err)
+		}
+		if o.RestoreSnapshot.Setup == nil {
+			return fmt.Errorf("%w: RestoreSnapshot.Setup is required", ErrInvalidOptions)
+		}
+		if slices.ContainsFunc(o.RestoreSnapshot.SnapshotOptions, func(opt SnapshotOption) bool { return opt == nil }) {
+			return fmt.Errorf("%w: RestoreSnapshot.SnapshotOptions contains nil option", ErrInvalidOptions)
+		}
+	}
+
+	err = o.validateStartupTimeouts()
+	if err != nil {
+		return fmt.Errorf("failed to validateStartupTimeouts: %w", err)
+	}
+
+	return nil
+}
+
+func (o RunOptions) validateStartupTimeouts() (err error) {
+	if o.StartupTimeout < 0 {
+		return fmt.Errorf("%w: StartupTimeout can't be negative", ErrInvalidOptions)
+	}
+
+	for phase, timeout := range o.PhaseTimeouts {
+		err = phase.validate()
+		if err != nil {
+			return fmt.Errorf("%w: invalid PhaseTimeouts: %w", ErrInvalidOptions, err)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("%w: `%s` phase timeout can't be negative", ErrInvalidOptions, phase)
+		}
+	}
+
 	return nil
 }
 
-func (o RunOptions) toDockertest() (dockertestRunOptions *dockertest.RunOptions) {
-	return &dockertest.RunOptions{
-		Hostname:     o.Hostname,
-		Name:         o.Name,
-		Repository:   o.Repository,
-		Tag:          o.Tag,
-		Env:          o.Env,
-		Entrypoint:   o.Entrypoint,
-		Cmd:          o.Cmd,
-		Mounts:       o.HostConfig.Binds,
-		Links:        o.HostConfig.Links,
-		ExposedPorts: o.ExposedPorts,
-		ExtraHosts:   o.HostConfig.ExtraHosts,
-		CapAdd:       o.HostConfig.CapAdd,
-		SecurityOpt:  o.HostConfig.SecurityOpt,
-		DNS:          o.HostConfig.DNS,
-		WorkingDir:   o.WorkingDir,
-		NetworkID:    "",
-		Networks:     o.Networks,
-		Labels:       o.Labels,
-		Auth:         o.Auth,
-		PortBindings: o.HostConfig.PortBindings,
-		Privileged:   o.HostConfig.Privileged,
-		User:         o.User,
-		Tty:          o.Tty,
-		Platform:     o.Platform,
+func (h Hooks) validate() (err error) {
+	if slices.ContainsFunc(h.BeforeCreate, func(hook BeforeCreateHook) bool { return hook == nil }) {
+		return fmt.Errorf("%w: BeforeCreate hook is nil", ErrInvalidOptions)
+	}
+
+	hooksByPhase := map[string][]Hook{
+		"AfterCreate":     h.AfterCreate,
+		"AfterStart":      h.AfterStart,
+		"AfterReady":      h.AfterReady,
+		"BeforeTerminate": h.BeforeTerminate,
+		"AfterTerminate":  h.AfterTerminate,
+	}
+	for _, phase := range slices.Sorted(maps.Keys(hooksByPhase)) {
+		if slices.ContainsFunc(hooksByPhase[phase], func(hook Hook) bool { return hook == nil }) {
+			return fmt.Errorf("%w: %s hook is nil", ErrInvalidOptions, phase)
+		}
+	}
+
+	return nil
+}
+
+// image - returns image reference "repository:tag" ("latest" tag if it's empty).
+func (o RunOptions) image() string {
+	tag := o.Tag
+	if tag == "" {
+		tag = defaultImageTag
+	}
+
+	return o.Repository + ":" + tag
+}
+
+func (o RunOptions) toCreateContainerOptions(
+	ctx context.Context,
+) (createContainerOptions docker.CreateContainerOptions) {
+	var exposedPorts map[docker.Port]struct{}
+	if len(o.ExposedPorts) > 0 {
+		exposedPorts = make(map[docker.Port]struct{}, len(o.ExposedPorts))
+		for _, port := range o.ExposedPorts {
+			exposedPorts[docker.Port(port)] = struct{}{}
+		}
+	}
+
+	// daemons with API < 1.44 don't support more than one network at creation,
+	// other networks are connected after creation (see Pool.connectNetworks)
+	endpointsConfig := make(map[string]*docker.EndpointConfig, 1)
+	if len(o.Networks) != 0 {
+		network := o.Networks[0]
+		endpointsConfig[network.Network.ID] = &docker.EndpointConfig{ //nolint:exhaustruct
+			Aliases: o.NetworkAliases[network.Network.ID],
+		}
+	}
+
+	hostConfig := o.HostConfig
+
+	return docker.CreateContainerOptions{
+		Name: o.Name,
+		Config: &docker.Config{ //nolint:exhaustruct
+			Hostname:     o.Hostname,
+			Image:        o.image(),
+			Env:          o.Env,
+			Entrypoint:   o.Entrypoint,
+			Cmd:          o.Cmd,
+			ExposedPorts: exposedPorts,
+			WorkingDir:   o.WorkingDir,
+			Labels:       o.Labels,
+			User:         o.User,
+			Tty:          o.Tty,
+		},
+		HostConfig:       &hostConfig,
+		NetworkingConfig: &docker.NetworkingConfig{EndpointsConfig: endpointsConfig},
+		Context:          ctx,
 	}
 }
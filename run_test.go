@@ -2,8 +2,14 @@ package tcontainer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,7 +24,7 @@ import (
 const containerAPIPort = "80"
 
 // runBusybox - creates minimal configureated busybox container for tests.
-func runBusybox(ctx context.Context, customOpts ...RunOption) (pool Pool, container *dockertest.Resource, err error) {
+func runBusybox(ctx context.Context, customOpts ...RunOption) (pool Pool, container *Container, err error) {
 	startServerCMD := fmt.Sprintf(`echo 'Hello, World!' > /index.html && httpd -p %s -h / && tail -f /dev/null`, containerAPIPort)
 
 	opts := append([]RunOption{
@@ -40,8 +46,8 @@ func runBusybox(ctx context.Context, customOpts ...RunOption) (pool Pool, contai
 }
 
 // pingBusyboxContainerServer - we can use this to check that container is healthy.
-func pingBusyboxContainerServer(_ context.Context, container *dockertest.Resource) error {
-	endpoint := GetAPIEndpoints(container)[containerAPIPort]
+func pingBusyboxContainerServer(_ context.Context, container *Container) error {
+	endpoint := GetAPIEndpoints(container.Resource)[containerAPIPort]
 
 	resp, err := http.Get("http://" + endpoint.NetJoinHostPort())
 	if err != nil {
@@ -125,6 +131,444 @@ func Test_RunOptions_WithContainerName(t *testing.T) { //nolint:dupl // similar
 	}
 }
 
+func Test_RunOptions_helpers(t *testing.T) {
+	t.Parallel()
+
+	retryOperation := func(context.Context, *Container) (err error) { return nil }
+	hook := func(context.Context, *Container) (err error) { return nil }
+	testNetwork := &dockertest.Network{Network: &docker.Network{ID: "network_id", Name: "network"}}
+
+	type testCase struct {
+		name  string
+		opts  []RunOption
+		check func(require *require.Assertions, options RunOptions)
+		err   error
+	}
+	testCases := []testCase{
+		{
+			name: "WithTag",
+			opts: []RunOption{WithTag("1.36")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal("1.36", options.Tag)
+			},
+		},
+		{
+			name: "WithTag/empty",
+			opts: []RunOption{WithTag("")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithEnv",
+			opts: []RunOption{WithEnv("A", "1"), WithEnv("B", "2=2"), WithEnv("A", "3")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]string{"A=3", "B=2=2"}, options.Env)
+			},
+		},
+		{
+			name: "WithEnv/invalid_name",
+			opts: []RunOption{WithEnv("A=", "1")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithEnvMap",
+			opts: []RunOption{WithEnv("C", "0"), WithEnvMap(map[string]string{"C": "3", "B": "2", "A": "1"})},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]string{"C=3", "A=1", "B=2"}, options.Env)
+			},
+		},
+		{
+			name: "WithCmd/WithEntrypoint",
+			opts: []RunOption{WithCmd("first"), WithCmd("sh", "-c", "true"), WithEntrypoint("/bin/entrypoint")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]string{"sh", "-c", "true"}, options.Cmd)
+				require.Equal([]string{"/bin/entrypoint"}, options.Entrypoint)
+			},
+		},
+		{
+			name: "WithExposedPorts",
+			opts: []RunOption{WithExposedPorts("80", "53/udp"), WithExposedPorts("80", "443")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]string{"80", "53/udp", "443"}, options.ExposedPorts)
+			},
+		},
+		{
+			name: "WithPortBinding",
+			opts: []RunOption{WithExposedPorts("80"), WithPortBinding("80", "8080"), WithPortBinding("80", "8081"), WithPortBinding("80", "8080")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]string{"80"}, options.ExposedPorts)
+				require.Equal(map[docker.Port][]docker.PortBinding{
+					"80": {{HostPort: "8080"}, {HostPort: "8081"}},
+				}, options.HostConfig.PortBindings)
+			},
+		},
+		{
+			name: "WithPortBinding/invalid_host_port",
+			opts: []RunOption{WithPortBinding("80", "http")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithRandomHostPort",
+			opts: []RunOption{WithRandomHostPort("80"), WithRandomHostPort("80")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]string{"80"}, options.ExposedPorts)
+				require.Equal(map[docker.Port][]docker.PortBinding{"80": {{HostPort: ""}}}, options.HostConfig.PortBindings)
+			},
+		},
+		{
+			name: "WithFixedHostPort",
+			opts: []RunOption{WithFixedHostPort("80", "8080")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal(map[docker.Port][]docker.PortBinding{"80": {{HostPort: "8080"}}}, options.HostConfig.PortBindings)
+				require.EqualValues(defaultPortInUseMaxTries, options.PortInUse.MaxTries)
+			},
+		},
+		{
+			name: "mounts",
+			opts: []RunOption{
+				WithBindMount("internal/testing", "/testing", true),
+				WithTmpfs("/cache", 1<<20),
+				WithVolume("tcontainer-data", "/data"),
+				WithEphemeralVolume("/cache"), // rewrites tmpfs
+			},
+			check: func(require *require.Assertions, options RunOptions) {
+				volumeOptions := &docker.VolumeOptions{Labels: map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue}}
+				require.Len(options.HostConfig.Mounts, 3)
+				require.Equal(mountTypeBind, options.HostConfig.Mounts[0].Type)
+				require.True(filepath.IsAbs(options.HostConfig.Mounts[0].Source))
+				require.True(options.HostConfig.Mounts[0].ReadOnly)
+				require.Equal(docker.HostMount{Type: mountTypeVolume, Target: "/cache", VolumeOptions: volumeOptions}, options.HostConfig.Mounts[1])
+				require.Equal(docker.HostMount{Type: mountTypeVolume, Source: "tcontainer-data", Target: "/data", VolumeOptions: volumeOptions}, options.HostConfig.Mounts[2])
+			},
+		},
+		{
+			name: "WithBindMount/not_exists",
+			opts: []RunOption{WithBindMount("not_exists", "/data", false)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithTmpfs/relative_container_path",
+			opts: []RunOption{WithTmpfs("data", 0)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithVolume/invalid_name",
+			opts: []RunOption{WithVolume("/data", "/data")},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithNetwork",
+			opts: []RunOption{WithNetwork(testNetwork, "a"), WithNetwork(testNetwork, "b", "a"), WithNetworkMode("bridge")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]*dockertest.Network{testNetwork}, options.Networks)
+				require.Equal(map[string][]string{"network_id": {"a", "b"}}, options.NetworkAliases)
+				require.Equal("bridge", options.HostConfig.NetworkMode)
+			},
+		},
+		{
+			name: "WithNetwork/nil",
+			opts: []RunOption{WithNetwork(nil)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithNetworkMode/conflict_networks",
+			opts: []RunOption{WithNetwork(testNetwork), WithNetworkMode("none")},
+			err:  ErrOptionConflict,
+		},
+		{
+			name: "WithNetworkMode/conflict_port_bindings",
+			opts: []RunOption{WithNetworkMode("container:other"), WithRandomHostPort("80")},
+			err:  ErrOptionConflict,
+		},
+		{
+			name: "WithContainerLabels",
+			opts: []RunOption{WithContainerLabels(map[string]string{"team": "a"}), WithContainerLabels(map[string]string{"team": "b"})},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal(map[string]string{DefaultLabelKeyValue: DefaultLabelKeyValue, "team": "b"}, options.Labels)
+			},
+		},
+		{
+			name: "WithHooks",
+			opts: []RunOption{
+				WithHooks(Hooks{AfterStart: []Hook{hook}}),
+				WithHooks(Hooks{AfterStart: []Hook{hook}, BeforeTerminate: []Hook{hook}}),
+			},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Len(options.Hooks.AfterStart, 2)
+				require.Len(options.Hooks.BeforeTerminate, 1)
+				require.Empty(options.Hooks.AfterCreate)
+			},
+		},
+		{
+			name: "WithHooks/nil",
+			opts: []RunOption{WithHooks(Hooks{AfterReady: []Hook{nil}})},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithRestoreFromSnapshot",
+			opts: []RunOption{WithRestoreFromSnapshot("my-snapshot:v1", hook)},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal("my-snapshot:v1", options.RestoreSnapshot.Name)
+				require.NotNil(options.RestoreSnapshot.Setup)
+				require.Empty(options.RestoreSnapshot.SnapshotOptions)
+			},
+		},
+		{
+			name: "WithRestoreFromSnapshot/snapshot_options",
+			opts: []RunOption{WithRestoreFromSnapshot("my-snapshot", hook, WithBeforeSnapshot(hook))},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Len(options.RestoreSnapshot.SnapshotOptions, 1)
+			},
+		},
+		{
+			name: "WithRestoreFromSnapshot/nil_snapshot_option",
+			opts: []RunOption{WithRestoreFromSnapshot("my-snapshot", hook, nil)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithRestoreFromSnapshot/invalid_name",
+			opts: []RunOption{WithRestoreFromSnapshot("Invalid Name", hook)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithRestoreFromSnapshot/nil_setup",
+			opts: []RunOption{WithRestoreFromSnapshot("my-snapshot", nil)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithUser/WithWorkingDir",
+			opts: []RunOption{WithUser("nobody"), WithWorkingDir("/tmp")},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal("nobody", options.User)
+				require.Equal("/tmp", options.WorkingDir)
+			},
+		},
+		{
+			name: "WithReuse",
+			opts: []RunOption{WithReuse(true)},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.True(options.Reuse.Reuse)
+				require.True(options.Reuse.RecreateOnErr)
+			},
+		},
+		{
+			name: "WithRemoveOnExists",
+			opts: []RunOption{WithRemoveOnExists()},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.True(options.RemoveOnExists)
+			},
+		},
+		{
+			name: "WithReuse/WithRemoveOnExists",
+			opts: []RunOption{WithReuse(false), WithRemoveOnExists()},
+			err:  ErrOptionConflict,
+		},
+		{
+			name: "WithExpiry",
+			opts: []RunOption{WithExpiry(0)},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Zero(options.ContainerExpiry)
+			},
+		},
+		{
+			name: "WithExpiry/negative",
+			opts: []RunOption{WithExpiry(-time.Second)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithRetry",
+			opts: []RunOption{WithRetry(retryOperation, nil)},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.NotNil(options.Retry.Operation)
+				require.NotNil(options.Retry.Backoff)
+			},
+		},
+		{
+			name: "WithRetry/nil_operation",
+			opts: []RunOption{WithRetry(nil, nil)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithHostConfig",
+			opts: []RunOption{
+				WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.CapAdd = append(hostConfig.CapAdd, "NET_ADMIN") }),
+				WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.CapAdd = append(hostConfig.CapAdd, "SYS_TIME") }),
+			},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal([]string{"NET_ADMIN", "SYS_TIME"}, options.HostConfig.CapAdd)
+				require.True(options.HostConfig.AutoRemove) // default is kept
+			},
+		},
+		{
+			name: "WithStartupTimeout/WithPhaseTimeout",
+			opts: []RunOption{
+				WithStartupTimeout(time.Minute),
+				WithPhaseTimeout(StartupPhasePull, time.Second),
+				WithPhaseTimeout(StartupPhaseReady, time.Second*2),
+				WithPhaseTimeout(StartupPhasePull, time.Second*3),
+			},
+			check: func(require *require.Assertions, options RunOptions) {
+				require.Equal(time.Minute, options.StartupTimeout)
+				require.Equal(map[StartupPhase]time.Duration{
+					StartupPhasePull:  time.Second * 3,
+					StartupPhaseReady: time.Second * 2,
+				}, options.PhaseTimeouts)
+			},
+		},
+		{
+			name: "WithStartupTimeout/negative",
+			opts: []RunOption{WithStartupTimeout(-time.Second)},
+			err:  ErrInvalidOptions,
+		},
+		{
+			name: "WithPhaseTimeout/unknown_phase",
+			opts: []RunOption{WithPhaseTimeout("build", time.Second)},
+			err:  ErrInvalidOptions,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			require := require.New(t)
+
+			options, err := ApplyRunOptions("busybox", test.opts...)
+			require.ErrorIs(err, test.err)
+			if test.check != nil {
+				test.check(require, options)
+			}
+		})
+	}
+}
+
+func Test_RunOptions_WithRandomHostPort(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	_, container, err := runBusybox(context.Background(), WithRandomHostPort(containerAPIPort))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	endpoint, ok := GetHostEndpoints(container.Resource)[containerAPIPort]
+	require.True(ok)
+	require.NotEqual("0", endpoint.Port)
+
+	resp, err := http.Get("http://" + endpoint.NetJoinHostPort())
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func Test_RunOptions_WithFixedHostPort(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	// occupy host port
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(err)
+	t.Cleanup(func() { _ = listener.Close() })
+	hostPort := fmt.Sprint(listener.Addr().(*net.TCPAddr).Port)
+
+	_, _, err = runBusybox(
+		context.Background(),
+		WithFixedHostPort(containerAPIPort, hostPort),
+		func(options *RunOptions) (err error) {
+			options.PortInUse.MaxTries = 2
+			return nil
+		},
+	)
+	require.ErrorIs(err, ErrPortInUse)
+
+	// release host port
+	require.NoError(listener.Close())
+
+	_, container, err := runBusybox(context.Background(), WithFixedHostPort(containerAPIPort, hostPort))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+	require.Equal(hostPort, GetHostEndpoints(container.Resource)[containerAPIPort].Port)
+}
+
+func Test_RunOptions_Mounts(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	volumeName := containerNameInvalidCharsRegexp.ReplaceAllString(t.Name(), "-")
+	opts := []RunOption{
+		WithContainerName(t.Name()),
+		WithBindMount("internal/testing", "/testing", true),
+		WithTmpfs("/cache", 1<<20),
+		WithVolume(volumeName, "/data"),
+		WithEphemeralVolume("/ephemeral"),
+		WithHostConfig(func(hostConfig *docker.HostConfig) { hostConfig.AutoRemove = false }),
+	}
+
+	pool, container, err := runBusybox(context.Background(), opts...)
+	require.NoError(err)
+	t.Cleanup(func() { _ = container.Close() })
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveVolume(volumeName) })
+
+	exec := func(container *Container, cmd string) int {
+		result, err := container.Exec(context.Background(), "sh", "-c", cmd)
+		require.NoError(err)
+		return result.ExitCode
+	}
+
+	require.Zero(exec(container, "test -f /testing/Dockerfile.test"))
+	require.NotZero(exec(container, "touch /testing/new_file"), "bind mount must be read only")
+	require.Zero(exec(container, "mount | grep '/cache' | grep tmpfs"))
+	require.Zero(exec(container, "echo data > /data/file && echo data > /ephemeral/file"))
+
+	// volumes are labelled
+	volume, err := pool.Pool.Client.InspectVolume(volumeName)
+	require.NoError(err)
+	require.Equal(DefaultLabelKeyValue, volume.Labels[DefaultLabelKeyValue])
+
+	ephemeralIdx := slices.IndexFunc(container.Container.Mounts, func(m docker.Mount) bool { return m.Destination == "/ephemeral" })
+	require.NotEqual(-1, ephemeralIdx)
+	ephemeralVolumeName := container.Container.Mounts[ephemeralIdx].Name
+	volume, err = pool.Pool.Client.InspectVolume(ephemeralVolumeName)
+	require.NoError(err)
+	require.Equal(DefaultLabelKeyValue, volume.Labels[DefaultLabelKeyValue])
+
+	// volumes are kept on reuse
+	_, reusedContainer, err := runBusybox(context.Background(), append(opts, WithReuse(false))...)
+	require.NoError(err)
+	require.Equal(container.Container.ID, reusedContainer.Container.ID)
+	require.Zero(exec(reusedContainer, "test -f /data/file && test -f /ephemeral/file"))
+
+	// reuse conflict on other mounts
+	_, _, err = runBusybox(context.Background(), append(opts, WithReuse(false), WithEphemeralVolume("/other"))...)
+	require.ErrorIs(err, ErrReuseContainerConflict)
+
+	// ephemeral volume and named volume created by the run are removed with the container
+	require.NoError(container.Close())
+	_, err = pool.Pool.Client.InspectVolume(ephemeralVolumeName)
+	require.ErrorIs(err, docker.ErrNoSuchVolume)
+	_, err = pool.Pool.Client.InspectVolume(volumeName)
+	require.ErrorIs(err, docker.ErrNoSuchVolume)
+}
+
+func Test_GetHostEndpoints(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	container := &dockertest.Resource{Container: &docker.Container{NetworkSettings: &docker.NetworkSettings{
+		Ports: map[docker.Port][]docker.PortBinding{
+			"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "32768"}, {HostIP: "::", HostPort: "32768"}},
+			"443/tcp":  {{HostIP: "::", HostPort: "32769"}},
+			"53/udp":   {{HostIP: "192.168.1.2", HostPort: "32770"}},
+			"9000/tcp": nil,
+		},
+	}}}
+
+	require.Equal(map[PrivatePort]APIEndpoint{
+		"80":  {IP: "127.0.0.1", Port: "32768"},
+		"443": {IP: "127.0.0.1", Port: "32769"},
+		"53":  {IP: "192.168.1.2", Port: "32770"},
+	}, GetHostEndpoints(container))
+}
+
 func Test_RunOptions_ContainerExpiry(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -178,71 +622,79 @@ func Test_RunOptions_Reuse_true(t *testing.T) {
 	type testCase struct {
 		skip                string `exhaustruct:"optional"`
 		name                string
-		invalidateContainer func(require *require.Assertions, pool Pool, container *dockertest.Resource)
+		invalidateContainer func(require *require.Assertions, pool Pool, container *Container)
+		expectedOrigin      ContainerOrigin
 	}
 	testCases := []testCase{
 		{
 			name: "Running",
-			invalidateContainer: func(require *require.Assertions, pool Pool, container *dockertest.Resource) {
+			invalidateContainer: func(require *require.Assertions, pool Pool, container *Container) {
 				dcontainer, err := pool.Pool.Client.InspectContainer(container.Container.ID)
 				require.NoError(err)
 				require.True(dcontainer.State.Running)
 			},
+			expectedOrigin: ContainerOriginReused,
 		},
 		{
 			name: "Paused",
-			invalidateContainer: func(require *require.Assertions, pool Pool, container *dockertest.Resource) {
+			invalidateContainer: func(require *require.Assertions, pool Pool, container *Container) {
 				require.NoError(pool.Pool.Client.PauseContainer(container.Container.ID))
 				dcontainer, err := pool.Pool.Client.InspectContainer(container.Container.ID)
 				require.NoError(err)
 				require.True(dcontainer.State.Paused)
 			},
+			expectedOrigin: ContainerOriginRepaired,
 		},
 		{
 			name: "Exited",
-			invalidateContainer: func(require *require.Assertions, pool Pool, container *dockertest.Resource) {
+			invalidateContainer: func(require *require.Assertions, pool Pool, container *Container) {
 				require.NoError(pool.Pool.Client.KillContainer(docker.KillContainerOptions{ID: container.Container.ID, Signal: docker.SIGKILL}))
 				dcontainer, err := pool.Pool.Client.InspectContainer(container.Container.ID)
 				require.NoError(err)
 				require.Equal("exited", dcontainer.State.Status)
 			},
+			expectedOrigin: ContainerOriginRepaired,
 		},
 		{
 			name: "Restarting",
 			skip: "i don't know how to write stable test for this case",
-			invalidateContainer: func(require *require.Assertions, pool Pool, container *dockertest.Resource) {
+			invalidateContainer: func(require *require.Assertions, pool Pool, container *Container) {
 				require.NoError(pool.Pool.Client.RestartContainer(container.Container.ID, 0))
 				dcontainer, err := pool.Pool.Client.InspectContainer(container.Container.ID)
 				require.NoError(err)
 				require.True(dcontainer.State.Restarting)
 			},
+			expectedOrigin: ContainerOriginRepaired,
 		},
 		{
 			name: "OOMKilled",
 			skip: "i don't know how to write stable test for this case",
-			invalidateContainer: func(require *require.Assertions, pool Pool, container *dockertest.Resource) {
+			invalidateContainer: func(require *require.Assertions, pool Pool, container *Container) {
 				dcontainer, err := pool.Pool.Client.InspectContainer(container.Container.ID)
 				require.NoError(err)
 				require.True(dcontainer.State.OOMKilled)
 			},
+			expectedOrigin: ContainerOriginRepaired,
 		},
 		{
 			name: "Dead",
 			skip: "i don't know how to write stable test for this case",
-			invalidateContainer: func(require *require.Assertions, pool Pool, container *dockertest.Resource) {
+			invalidateContainer: func(require *require.Assertions, pool Pool, container *Container) {
 				dcontainer, err := pool.Pool.Client.InspectContainer(container.Container.ID)
 				require.NoError(err)
 				require.True(dcontainer.State.Dead)
 			},
+			expectedOrigin: ContainerOriginRepaired,
 		},
 		{
 			name: "RemovalInProgress",
 			skip: "i don't know how to write stable test for this case",
-			invalidateContainer: func(require *require.Assertions, pool Pool, container *dockertest.Resource) {
+			invalidateContainer: func(require *require.Assertions, pool Pool, container *Container) {
 				dcontainer, err := pool.Pool.Client.InspectContainer(container.Container.ID)
 				require.NoError(err)
 				require.True(dcontainer.State.RemovalInProgress)
 			},
+			expectedOrigin: ContainerOriginRepaired,
 		},
 	}
 	for _, test := range testCases {
@@ -281,6 +733,7 @@ func Test_RunOptions_Reuse_true(t *testing.T) {
 			require.NoError(err)
 			require.Equal(containerIDSrc, container.Container.ID)               // check we reuse the container
 			require.NoError(pingBusyboxContainerServer(t.Context(), container)) // check container is ok
+			require.Equal(RunOutcome{Origin: test.expectedOrigin, ReuseErr: nil}, container.Outcome())
 		})
 	}
 }
@@ -312,6 +765,8 @@ func Test_RunOptions_Reuse_RecreateOnError(t *testing.T) {
 	newContainerID := container.Container.ID
 	assert.NotEmpty(newContainerID)
 	require.NotEqual(oldContainerID, newContainerID)
+	require.Equal(ContainerOriginRecreated, container.Outcome().Origin)
+	require.ErrorIs(container.Outcome().ReuseErr, ErrReuseContainerConflict)
 }
 
 func Test_RunOptions_RemoveOnExists(t *testing.T) {
@@ -528,3 +983,189 @@ func Test_checkContainerConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_RunOptions_WithHooks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var (
+		mu     sync.Mutex
+		phases []string
+	)
+	hook := func(phase string, check Hook) Hook {
+		return func(ctx context.Context, container *Container) (err error) {
+			mu.Lock()
+			phases = append(phases, phase)
+			mu.Unlock()
+
+			if check != nil {
+				return check(ctx, container)
+			}
+			return nil
+		}
+	}
+	takePhases := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		taken := phases
+		phases = nil
+		return taken
+	}
+
+	hooks := Hooks{
+		BeforeCreate: []BeforeCreateHook{func(_ context.Context, options *RunOptions) (err error) {
+			mu.Lock()
+			phases = append(phases, "BeforeCreate")
+			mu.Unlock()
+			options.Env = append(options.Env, "HOOK=1")
+			return nil
+		}},
+		AfterCreate: []Hook{hook("AfterCreate", func(_ context.Context, container *Container) error {
+			if container.Container.State.Running {
+				return errors.New("container is running before start")
+			}
+			return nil
+		})},
+		AfterStart: []Hook{hook("AfterStart", nil)},
+		AfterReady: []Hook{hook("AfterReady", func(ctx context.Context, container *Container) error {
+			return pingBusyboxContainerServer(ctx, container)
+		})},
+		BeforeTerminate: []Hook{hook("BeforeTerminate", nil)},
+		AfterTerminate:  []Hook{hook("AfterTerminate", nil)},
+	}
+
+	// create
+	pool, container, err := runBusybox(context.Background(), WithContainerName(t.Name()), WithReuse(false), WithHooks(hooks))
+	require.NoError(err)
+	require.Contains(container.Container.Config.Env, "HOOK=1")
+	require.Equal([]string{"BeforeCreate", "AfterCreate", "AfterStart", "AfterReady"}, takePhases())
+
+	// reuse
+	reusedContainer, err := pool.Run(context.Background(), "busybox", WithContainerName(t.Name()), WithReuse(false), WithHooks(hooks))
+	require.NoError(err)
+	require.Equal(container.Container.ID, reusedContainer.Container.ID)
+	require.Equal([]string{"BeforeCreate", "AfterStart", "AfterReady"}, takePhases())
+
+	// terminate
+	err = container.Terminate(context.Background())
+	require.NoError(err)
+	require.Equal([]string{"BeforeTerminate", "AfterTerminate"}, takePhases())
+	_, ok := pool.Pool.ContainerByName(fmt.Sprintf("^%s$", formatContainerName(t.Name())))
+	require.False(ok)
+}
+
+func Test_RunOptions_WithHooks_Error(t *testing.T) {
+	t.Parallel()
+
+	errHook := errors.New("hook error")
+	failingHook := func(context.Context, *Container) (err error) { return errHook }
+
+	testCases := []struct {
+		name  string
+		hooks Hooks
+	}{
+		{
+			name: "BeforeCreate",
+			hooks: Hooks{BeforeCreate: []BeforeCreateHook{
+				func(context.Context, *RunOptions) (err error) { return errHook },
+			}},
+		},
+		{name: "AfterCreate", hooks: Hooks{AfterCreate: []Hook{failingHook}}},
+		{name: "AfterStart", hooks: Hooks{AfterStart: []Hook{failingHook}}},
+		{name: "AfterReady", hooks: Hooks{AfterReady: []Hook{failingHook}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			var terminated atomic.Bool
+			tc.hooks.BeforeTerminate = []Hook{func(context.Context, *Container) (err error) {
+				terminated.Store(true)
+				return nil
+			}}
+
+			_, _, err := runBusybox(context.Background(), WithContainerName(t.Name()), WithHooks(tc.hooks))
+			require.ErrorIs(err, errHook)
+
+			// container is purged
+			_, ok := MustNewPool("").Pool.ContainerByName(fmt.Sprintf("^%s$", formatContainerName(t.Name())))
+			require.False(ok)
+			require.Equal(tc.name != "BeforeCreate", terminated.Load())
+		})
+	}
+}
+
+func Test_isImageNotFoundErr(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "no such image", err: fmt.Errorf("failed: %w", docker.ErrNoSuchImage), expected: true},
+		{name: "manifest unknown", err: &docker.Error{Status: http.StatusNotFound, Message: "manifest unknown"}, expected: true},
+		{
+			name:     "pull access denied",
+			err:      &docker.Error{Status: http.StatusNotFound, Message: "pull access denied for app, repository does not exist"},
+			expected: true,
+		},
+		{name: "other not found", err: &docker.Error{Status: http.StatusNotFound, Message: "network not found"}, expected: false},
+		{name: "other error", err: errors.New("connection refused"), expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, isImageNotFoundErr(tc.err))
+		})
+	}
+}
+
+func Test_hasAssignedHostPorts(t *testing.T) {
+	t.Parallel()
+
+	newContainer := func(bindings map[docker.Port][]docker.PortBinding, publishAll bool) *docker.Container {
+		return &docker.Container{ //nolint:exhaustruct
+			Config: &docker.Config{ExposedPorts: map[docker.Port]struct{}{"80/tcp": {}, "81/tcp": {}}}, //nolint:exhaustruct
+			HostConfig: &docker.HostConfig{ //nolint:exhaustruct
+				PortBindings:    map[docker.Port][]docker.PortBinding{"80/tcp": {{HostIP: "", HostPort: ""}}},
+				PublishAllPorts: publishAll,
+			},
+			NetworkSettings: &docker.NetworkSettings{Ports: bindings}, //nolint:exhaustruct
+		}
+	}
+	assigned := []docker.PortBinding{{HostIP: "0.0.0.0", HostPort: "32768"}}
+
+	testCases := []struct {
+		name      string
+		container *docker.Container
+		expected  bool
+	}{
+		{name: "assigned", container: newContainer(map[docker.Port][]docker.PortBinding{"80/tcp": assigned}, false), expected: true},
+		{name: "not_assigned", container: newContainer(nil, false), expected: false},
+		{
+			name:      "zero_port",
+			container: newContainer(map[docker.Port][]docker.PortBinding{"80/tcp": {{HostIP: "", HostPort: "0"}}}, false),
+			expected:  false,
+		},
+		{
+			name:      "publish_all/not_assigned",
+			container: newContainer(map[docker.Port][]docker.PortBinding{"80/tcp": assigned}, true),
+			expected:  false,
+		},
+		{
+			name: "publish_all/assigned",
+			container: newContainer(
+				map[docker.Port][]docker.PortBinding{"80/tcp": assigned, "81/tcp": assigned}, true,
+			),
+			expected: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, hasAssignedHostPorts(tc.container))
+		})
+	}
+}
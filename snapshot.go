@@ -0,0 +1,118 @@
+package tcontainer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// Snapshot - commits the container filesystem into the image labelled with DefaultLabelKeyValue
+// (so Prune removes it), e.g. to run new containers with prepared data by WithRestoreFromSnapshot.
+//   - name - image reference, "latest" tag is used if it's empty (e.g. "my-app-db-snapshot:v3").
+//   - Rewrites existing snapshot with the same name.
+//   - Data in volumes (including VOLUME of the image, e.g. postgres /var/lib/postgresql/data)
+//     is not committed - store data outside of volumes (e.g. set PGDATA) to snapshot it.
+func (p Pool) Snapshot(
+	ctx context.Context, container *Container, name string, customOpts ...SnapshotOption,
+) (image *docker.Image, err error) {
+	if container == nil {
+		return nil, fmt.Errorf("%w: container is required", ErrInvalidOptions)
+	}
+
+	options, err := ApplySnapshotOptions(customOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ApplySnapshotOptions: %w", err)
+	}
+
+	repository, tag, err := parseImageRef(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parseImageRef: %w", err)
+	}
+
+	err = runHooks(ctx, container, options.BeforeSnapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run BeforeSnapshot hook: %w", err)
+	}
+
+	image, err = p.Pool.Client.CommitContainer(docker.CommitContainerOptions{
+		Container:  container.Container.ID,
+		Repository: repository,
+		Tag:        tag,
+		Message:    "tcontainer snapshot",
+		Author:     "",
+		Changes:    []string{fmt.Sprintf("LABEL %s=%s", DefaultLabelKeyValue, DefaultLabelKeyValue)},
+		Run:        nil,
+		Context:    ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to CommitContainer: %w", err)
+	}
+
+	return image, nil
+}
+
+// applyRestoreSnapshot - runs container from the snapshot if it exists,
+// otherwise adds AfterReady hooks that run setup and create the snapshot.
+func (p Pool) applyRestoreSnapshot(
+	ctx context.Context, options RunOptions,
+) (_ RunOptions, restored bool, err error) {
+	snapshot := options.RestoreSnapshot
+
+	_, err = p.inspectImage(ctx, snapshot.Name)
+	switch {
+	case err == nil:
+		options.RestoreSnapshot.baseImage = options.Repository + ":" + options.Tag
+		options.Repository, options.Tag, err = parseImageRef(snapshot.Name)
+		if err != nil {
+			return RunOptions{}, false, fmt.Errorf("failed to parseImageRef: %w", err)
+		}
+
+		return options, true, nil
+
+	case errors.Is(err, docker.ErrNoSuchImage):
+		setupAndSnapshot := func(ctx context.Context, container *Container) (err error) {
+			// reused container already has the data
+			origin := container.Outcome().Origin
+			if origin != ContainerOriginCreated && origin != ContainerOriginRecreated {
+				return nil
+			}
+
+			err = snapshot.Setup(ctx, container)
+			if err != nil {
+				return fmt.Errorf("failed to run snapshot setup: %w", err)
+			}
+
+			_, err = p.Snapshot(ctx, container, snapshot.Name, snapshot.SnapshotOptions...)
+			if err != nil {
+				return fmt.Errorf("failed to Snapshot: %w", err)
+			}
+
+			return nil
+		}
+		options.Hooks.AfterReady = slices.Concat([]Hook{setupAndSnapshot}, options.Hooks.AfterReady)
+
+		return options, false, nil
+
+	default:
+		return RunOptions{}, false, fmt.Errorf("failed to InspectImage: %w", err)
+	}
+}
+
+// isSnapshotImage - checks that the container image is the snapshot image or its base image
+// (e.g. reused container was created before the snapshot), so both are accepted on reuse.
+func isSnapshotImage(image string, options RunOptions) bool {
+	snapshot := options.RestoreSnapshot
+	if snapshot.Name == "" {
+		return false
+	}
+
+	repository, tag, err := parseImageRef(snapshot.Name)
+	if err != nil {
+		return false
+	}
+
+	return image == repository+":"+tag || (snapshot.baseImage != "" && image == snapshot.baseImage)
+}
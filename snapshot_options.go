@@ -0,0 +1,67 @@
+package tcontainer
+
+import (
+	"fmt"
+	"slices"
+)
+
+type (
+	// SnapshotOptions for (Pool).Snapshot function.
+	SnapshotOptions struct {
+		// BeforeSnapshot - hooks that run before commit (e.g. `CHECKPOINT` for the database).
+		BeforeSnapshot []Hook
+	}
+
+	// SnapshotOption - option for (Pool).Snapshot function.
+	// See [ApplySnapshotOptions].
+	SnapshotOption func(options *SnapshotOptions) (err error)
+)
+
+// WithBeforeSnapshot - add hook that runs before commit (e.g. flush database data to disk).
+func WithBeforeSnapshot(hook Hook) SnapshotOption {
+	return func(options *SnapshotOptions) (err error) {
+		if hook == nil {
+			return fmt.Errorf("%w: hook is nil", ErrInvalidOptions)
+		}
+
+		options.BeforeSnapshot = append(options.BeforeSnapshot, hook)
+
+		return nil
+	}
+}
+
+// ApplySnapshotOptions sets defaults and apply custom options.
+// Options aplies in order they passed.
+//
+// WithBeforeSnapshot accumulates hooks.
+func ApplySnapshotOptions(customOpts ...SnapshotOption) (options SnapshotOptions, err error) {
+	options = options.getDefault()
+
+	for _, customOpt := range customOpts {
+		err = customOpt(&options)
+		if err != nil {
+			return SnapshotOptions{}, err
+		}
+	}
+
+	err = options.validate()
+	if err != nil {
+		return SnapshotOptions{}, fmt.Errorf("failed to options.validate: %w", err)
+	}
+
+	return options, nil
+}
+
+func (o SnapshotOptions) getDefault() (defaultOptions SnapshotOptions) {
+	return SnapshotOptions{
+		BeforeSnapshot: nil,
+	}
+}
+
+func (o SnapshotOptions) validate() (err error) {
+	if slices.ContainsFunc(o.BeforeSnapshot, func(hook Hook) bool { return hook == nil }) {
+		return fmt.Errorf("%w: BeforeSnapshot hook is nil", ErrInvalidOptions)
+	}
+
+	return nil
+}
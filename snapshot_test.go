@@ -0,0 +1,182 @@
+package tcontainer
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiteggrad/tcontainer/dockerfake"
+)
+
+func Test_Snapshot(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool, container, err := runBusybox(context.Background())
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+
+	// prepare data
+	result, err := container.Exec(context.Background(), "sh", "-c", "echo prepared > /data.txt")
+	require.NoError(err)
+	require.Zero(result.ExitCode, result.Stderr)
+
+	// snapshot
+	var hookCalled atomic.Bool
+	snapshotName := "tcontainer-snapshot-" + strings.ToLower(uuid.NewString())
+	image, err := pool.Snapshot(context.Background(), container, snapshotName, WithBeforeSnapshot(
+		func(ctx context.Context, container *Container) (err error) {
+			hookCalled.Store(true)
+			_, err = container.Exec(ctx, "sync")
+			return err
+		},
+	))
+	require.NoError(err)
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveImageExtended(image.ID, docker.RemoveImageOptions{Force: true}) })
+	require.True(hookCalled.Load())
+	require.Equal(DefaultLabelKeyValue, image.Config.Labels[DefaultLabelKeyValue])
+
+	// restore
+	setup := func(context.Context, *Container) (err error) {
+		require.Fail("setup must not be called for existing snapshot")
+		return nil
+	}
+	_, restoredContainer, err := runBusybox(context.Background(), WithRestoreFromSnapshot(snapshotName, setup))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(restoredContainer.Close()) })
+
+	require.True(restoredContainer.Outcome().RestoredFromSnapshot)
+	require.Equal(snapshotName+":latest", restoredContainer.Container.Config.Image)
+	content, err := restoredContainer.CopyFrom(context.Background(), "/data.txt")
+	require.NoError(err)
+	require.Equal("prepared\n", string(content))
+
+	// invalid args
+	_, err = pool.Snapshot(context.Background(), nil, snapshotName)
+	require.ErrorIs(err, ErrInvalidOptions)
+	_, err = pool.Snapshot(context.Background(), container, "Invalid Name")
+	require.ErrorIs(err, ErrInvalidOptions)
+}
+
+func Test_RunOptions_WithRestoreFromSnapshot_Fallback(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	assert := assert.New(t)
+
+	pool := MustNewPool("")
+
+	var setupCalls atomic.Int32
+	setup := func(ctx context.Context, container *Container) (err error) {
+		setupCalls.Add(1)
+		return container.CopyTo(ctx, "/data.txt", []byte("prepared"), 0o644)
+	}
+
+	var beforeSnapshotCalls atomic.Int32
+	beforeSnapshot := func(context.Context, *Container) (err error) {
+		beforeSnapshotCalls.Add(1)
+		return nil
+	}
+
+	// snapshot doesn't exist - setup and create snapshot
+	snapshotName := "tcontainer-snapshot-" + strings.ToLower(uuid.NewString())
+	containerName := "tcontainer-snapshot-" + strings.ToLower(uuid.NewString())
+	_, container, err := runBusybox(
+		context.Background(),
+		WithContainerName(containerName), WithReuse(false),
+		WithRestoreFromSnapshot(snapshotName, setup, WithBeforeSnapshot(beforeSnapshot)),
+	)
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(container.Close()) })
+	t.Cleanup(func() { _ = pool.Pool.Client.RemoveImageExtended(snapshotName, docker.RemoveImageOptions{Force: true}) })
+
+	require.False(container.Outcome().RestoredFromSnapshot)
+	require.Equal(int32(1), setupCalls.Load())
+	require.Equal(int32(1), beforeSnapshotCalls.Load())
+	image, err := pool.Pool.Client.InspectImage(snapshotName)
+	require.NoError(err)
+	require.Equal(DefaultLabelKeyValue, image.Config.Labels[DefaultLabelKeyValue])
+
+	// snapshot exists - restore without setup
+	_, restoredContainer, err := runBusybox(context.Background(), WithRestoreFromSnapshot(snapshotName, setup))
+	require.NoError(err)
+	t.Cleanup(func() { assert.NoError(restoredContainer.Close()) })
+
+	require.True(restoredContainer.Outcome().RestoredFromSnapshot)
+	require.Equal(int32(1), setupCalls.Load())
+	content, err := restoredContainer.CopyFrom(context.Background(), "/data.txt")
+	require.NoError(err)
+	require.Equal("prepared", string(content))
+
+	// snapshot exists - reuse container created from the base image
+	_, reusedContainer, err := runBusybox(
+		context.Background(),
+		WithContainerName(containerName), WithReuse(false),
+		WithRestoreFromSnapshot(snapshotName, setup),
+	)
+	require.NoError(err)
+	require.Equal(container.Container.ID, reusedContainer.Container.ID)
+	require.Equal(ContainerOriginReused, reusedContainer.Outcome().Origin)
+	require.Equal(int32(1), setupCalls.Load())
+}
+
+func Test_RunOptions_WithRestoreFromSnapshot_Canceled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	server := dockerfake.NewServer()
+	t.Cleanup(server.Close)
+	pool := NewPoolWithClient(server.Client())
+
+	// hung lookup of the snapshot is interrupted by ctx
+	server.Fail(dockerfake.Failure{Method: http.MethodGet, Path: `^/images/my-snapshot:v1/json$`, Delay: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	setup := func(context.Context, *Container) (err error) { return nil }
+	_, err := pool.Run(ctx, "busybox", WithRestoreFromSnapshot("my-snapshot:v1", setup))
+	require.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func Test_isSnapshotImage(t *testing.T) {
+	t.Parallel()
+
+	restored := RunOptions{ //nolint:exhaustruct
+		Repository:      "my-snapshot",
+		Tag:             "v1",
+		RestoreSnapshot: RestoreSnapshotOptions{Name: "my-snapshot:v1", baseImage: "postgres:16"}, //nolint:exhaustruct
+	}
+	notRestored := RunOptions{ //nolint:exhaustruct
+		Repository:      "postgres",
+		Tag:             "16",
+		RestoreSnapshot: RestoreSnapshotOptions{Name: "my-snapshot"}, //nolint:exhaustruct
+	}
+
+	testCases := []struct {
+		name     string
+		image    string
+		options  RunOptions
+		expected bool
+	}{
+		{name: "restored/snapshot", image: "my-snapshot:v1", options: restored, expected: true},
+		{name: "restored/base", image: "postgres:16", options: restored, expected: true},
+		{name: "restored/other", image: "postgres:15", options: restored, expected: false},
+		{name: "not_restored/snapshot", image: "my-snapshot:latest", options: notRestored, expected: true},
+		{name: "not_restored/other", image: "my-snapshot:v1", options: notRestored, expected: false},
+		{name: "no_snapshot", image: "postgres:16", options: RunOptions{}, expected: false}, //nolint:exhaustruct
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expected, isSnapshotImage(tc.image, tc.options))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package tcontainer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Phases of the container startup (see WithStartupTimeout(), WithPhaseTimeout()).
+const (
+	// StartupPhasePull - pull of the image if it doesn't exist.
+	StartupPhasePull StartupPhase = "pull"
+	// StartupPhaseCreate - creation of the container, including BeforeCreate and AfterCreate hooks.
+	StartupPhaseCreate StartupPhase = "create"
+	// StartupPhaseStart - start of the container (or reuse of the existing one), including AfterStart hooks.
+	StartupPhaseStart StartupPhase = "start"
+	// StartupPhaseReady - readiness check (see WithRetry()), including AfterReady hooks.
+	StartupPhaseReady StartupPhase = "ready"
+)
+
+type (
+	// StartupPhase - phase of the container startup.
+	StartupPhase string
+
+	// StartupTimeoutError - occurs when the container startup exceeds StartupTimeout or timeout of the phase
+	// (see WithStartupTimeout(), WithPhaseTimeout()).
+	//   - errors.Is(err, ErrStartupTimeout) and errors.Is(err, context.DeadlineExceeded) are true.
+	StartupTimeoutError struct {
+		// Phase - phase that was interrupted by the deadline.
+		Phase StartupPhase
+		// Timeout - exceeded timeout.
+		Timeout time.Duration
+		// Total - true if StartupTimeout is exceeded, false if timeout of the Phase is exceeded.
+		Total bool
+		// Err - error of the interrupted operation.
+		Err error
+	}
+)
+
+func (e *StartupTimeoutError) Error() string {
+	msg := fmt.Sprintf("%s: `%s` phase timeout %s exceeded", ErrStartupTimeout, e.Phase, e.Timeout)
+	if e.Total {
+		msg = fmt.Sprintf("%s: timeout %s exceeded in `%s` phase", ErrStartupTimeout, e.Timeout, e.Phase)
+	}
+
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *StartupTimeoutError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrStartupTimeout, context.DeadlineExceeded}
+	}
+
+	return []error{ErrStartupTimeout, context.DeadlineExceeded, e.Err}
+}
+
+func (p StartupPhase) validate() (err error) {
+	switch p {
+	case StartupPhasePull, StartupPhaseCreate, StartupPhaseStart, StartupPhaseReady:
+		return nil
+	default:
+		return fmt.Errorf("unknown startup phase `%s`", p)
+	}
+}
+
+// startupContext - returns ctx limited by StartupTimeout of the options.
+func (o RunOptions) startupContext(ctx context.Context) (startupCtx context.Context, cancel context.CancelFunc) {
+	if o.StartupTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	cause := &StartupTimeoutError{Phase: "", Timeout: o.StartupTimeout, Total: true, Err: nil}
+
+	return context.WithTimeoutCause(ctx, o.StartupTimeout, cause)
+}
+
+// phaseContext - returns ctx limited by timeout of the phase (see RunOptions.PhaseTimeouts).
+func (o RunOptions) phaseContext(
+	ctx context.Context, phase StartupPhase,
+) (phaseCtx context.Context, cancel context.CancelFunc) {
+	timeout := o.PhaseTimeouts[phase]
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	cause := &StartupTimeoutError{Phase: phase, Timeout: timeout, Total: false, Err: nil}
+
+	return context.WithTimeoutCause(ctx, timeout, cause)
+}
+
+// runStartupPhase - runs the phase with timeout of the phase, see startupPhaseError.
+//   - Returned error is marked with the phase for RunError.
+//   - The phase is reported to the instrumentation of the pool.
+func (p Pool) runStartupPhase(
+	ctx context.Context, options RunOptions, phase StartupPhase, run func(ctx context.Context) (err error),
+) (err error) {
+	ctx, end := p.instrument().Start(ctx, phaseOperation(phase), options.operationInfo())
+	defer func() { end(OperationResult{Err: err, ContainerID: "", Origin: ""}) }()
+
+	ctx, cancel := options.phaseContext(ctx, phase)
+	defer cancel()
+
+	err = startupPhaseError(ctx, phase, run(ctx))
+	if err != nil {
+		return &phaseError{phase: phase, err: err}
+	}
+
+	return nil
+}
+
+// startupPhaseError - returns *StartupTimeoutError if the phase was interrupted by startup or phase deadline,
+// returns err as is otherwise (e.g. on cancellation of the ctx by caller).
+func startupPhaseError(ctx context.Context, phase StartupPhase, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+
+	// already reported by the nested phase
+	var timeoutErr *StartupTimeoutError
+	if errors.As(err, &timeoutErr) && timeoutErr.Phase != "" {
+		return err
+	}
+
+	var cause *StartupTimeoutError
+	if !errors.As(context.Cause(ctx), &cause) {
+		return err
+	}
+
+	if errors.Is(err, cause) {
+		err = nil // the bare cause returned by backoff.Retry
+	}
+
+	return &StartupTimeoutError{Phase: phase, Timeout: cause.Timeout, Total: cause.Total, Err: err}
+}
@@ -0,0 +1,202 @@
+package tcontainer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+type (
+	// StartupStats - durations of the container startup phases (see (*Container).StartupStats()).
+	//   - Durations of the create and start phases include retries on port in use (see WithFixedHostPort()).
+	//   - Phases that were skipped (e.g. reused container isn't created) have zero duration.
+	StartupStats struct {
+		// Pull - check of the image and its pull if it doesn't exist.
+		Pull time.Duration
+		// Create - creation of the container, including AfterCreate hooks.
+		Create time.Duration
+		// Start - start of the created container.
+		Start time.Duration
+		// Ready - readiness check (see WithRetry()), including AfterReady hooks.
+		Ready time.Duration
+		// Total - time to ready, from the call of Run to the ready container.
+		Total time.Duration
+		// RetryAttempts - failed attempts of the readiness check before the container got ready.
+		RetryAttempts int
+	}
+
+	// PoolStats - aggregated StartupStats of the containers run by the Pool (see Pool.Stats()).
+	PoolStats struct {
+		// Images - stats by image of the container, the slowest (by sum of Total) first.
+		Images []ImageStats
+	}
+
+	// ImageStats - aggregated StartupStats of the containers of the image.
+	ImageStats struct {
+		Image string
+		// Runs - number of the successful runs.
+		Runs int
+		// Origins - number of the runs by origin of the container (e.g. reuse hits vs recreates).
+		Origins map[ContainerOrigin]int
+		// Sum - sum of StartupStats of the runs.
+		Sum StartupStats
+		// MaxTotal - the longest time to ready.
+		MaxTotal time.Duration
+	}
+)
+
+// StartupStats - returns durations of the startup phases of the container.
+func (c *Container) StartupStats() StartupStats {
+	return c.startupStats
+}
+
+// Stats - returns aggregated StartupStats of the containers successfully run by the Pool
+// (shared by the pools derived by WithDefaults(), WithLogger(), etc.), e.g. to print from TestMain
+// and decide which containers should be reused (see WithReuse()) or restored from snapshots.
+//   - Pool created without NewPool* functions doesn't collect stats.
+//
+// Example usage:
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		fmt.Println(pool.Stats())
+//		os.Exit(code)
+//	}
+func (p Pool) Stats() PoolStats {
+	if p.stats == nil {
+		return PoolStats{Images: nil}
+	}
+
+	return p.stats.snapshot()
+}
+
+// String - returns stats as a table.
+func (s PoolStats) String() string {
+	const (
+		minWidth = 0
+		tabWidth = 8
+		padding  = 2
+	)
+
+	builder := &strings.Builder{}
+	writer := tabwriter.NewWriter(builder, minWidth, tabWidth, padding, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "IMAGE\tRUNS\tREUSED\tTOTAL\tAVG\tMAX\tPULL\tCREATE\tSTART\tREADY\tRETRIES")
+
+	for _, image := range s.Images {
+		reused := image.Origins[ContainerOriginReused] + image.Origins[ContainerOriginRepaired]
+		_, _ = fmt.Fprintf(writer, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			image.Image, image.Runs, reused,
+			roundDuration(image.Sum.Total), roundDuration(image.Sum.Total/time.Duration(image.Runs)),
+			roundDuration(image.MaxTotal), roundDuration(image.Sum.Pull), roundDuration(image.Sum.Create),
+			roundDuration(image.Sum.Start), roundDuration(image.Sum.Ready), image.Sum.RetryAttempts,
+		)
+	}
+	_ = writer.Flush()
+
+	return builder.String()
+}
+
+func roundDuration(duration time.Duration) time.Duration {
+	return duration.Round(time.Millisecond)
+}
+
+// poolStats - aggregated stats of the pool, shared by copies of the Pool.
+type poolStats struct {
+	mu     sync.Mutex
+	images map[string]*ImageStats
+}
+
+func newPoolStats() *poolStats {
+	return &poolStats{mu: sync.Mutex{}, images: map[string]*ImageStats{}}
+}
+
+func (s *poolStats) add(image string, origin ContainerOrigin, stats StartupStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imageStats, ok := s.images[image]
+	if !ok {
+		imageStats = &ImageStats{Image: image, Runs: 0, Origins: map[ContainerOrigin]int{}, Sum: StartupStats{}, MaxTotal: 0}
+		s.images[image] = imageStats
+	}
+
+	imageStats.Runs++
+	imageStats.Origins[origin]++
+	imageStats.Sum.Pull += stats.Pull
+	imageStats.Sum.Create += stats.Create
+	imageStats.Sum.Start += stats.Start
+	imageStats.Sum.Ready += stats.Ready
+	imageStats.Sum.Total += stats.Total
+	imageStats.Sum.RetryAttempts += stats.RetryAttempts
+	imageStats.MaxTotal = max(imageStats.MaxTotal, stats.Total)
+}
+
+func (s *poolStats) snapshot() PoolStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	images := make([]ImageStats, 0, len(s.images))
+	for _, imageStats := range s.images {
+		image := *imageStats
+		image.Origins = maps.Clone(imageStats.Origins)
+		images = append(images, image)
+	}
+
+	slices.SortFunc(images, func(a, b ImageStats) int {
+		return cmp.Or(cmp.Compare(b.Sum.Total, a.Sum.Total), strings.Compare(a.Image, b.Image))
+	})
+
+	return PoolStats{Images: images}
+}
+
+// startupStatsCollector - Instrumentation that collects StartupStats of the run
+// from its phases and passes them to the next instrumentation.
+type startupStatsCollector struct {
+	next  Instrumentation
+	stats *StartupStats
+}
+
+// newStartupStatsCollector - returns collector of the stats of the run.
+//   - Collector of the outer run is skipped, so the nested run (e.g. Run in the hook) isn't counted in its stats.
+func newStartupStatsCollector(next Instrumentation, stats *StartupStats) startupStatsCollector {
+	if collector, ok := next.(startupStatsCollector); ok {
+		next = collector.next
+	}
+
+	return startupStatsCollector{next: next, stats: stats}
+}
+
+func (c startupStatsCollector) Start(
+	ctx context.Context, operation Operation, info OperationInfo,
+) (opCtx context.Context, end OperationEnd) {
+	start := time.Now()
+	ctx, nextEnd := c.next.Start(ctx, operation, info)
+
+	return ctx, func(result OperationResult) {
+		duration := time.Since(start)
+		switch operation {
+		case OperationPull:
+			c.stats.Pull += duration
+		case OperationCreate:
+			c.stats.Create += duration
+		case OperationStart:
+			c.stats.Start += duration
+		case OperationReady:
+			c.stats.Ready += duration
+		case OperationRun, OperationBuild, OperationPrune:
+		}
+
+		nextEnd(result)
+	}
+}
+
+func (c startupStatsCollector) RetryAttempt(ctx context.Context, info OperationInfo, attempt int, err error) {
+	c.stats.RetryAttempts++
+	c.next.RetryAttempt(ctx, info, attempt, err)
+}
@@ -2,11 +2,20 @@ package tcontainer
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"net"
+	"net/http"
+	"net/url"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
@@ -26,6 +35,16 @@ var (
 	ErrUnreusableState = errors.New("imposible to reuse container with it's current state")
 	// ErrReuseContainerConflict - occurs when existed container have different options (e.q. image tag).
 	ErrReuseContainerConflict = errors.New("imposible to reuse container, it has differnent options")
+	// ErrPortInUse - occurs when it's impossible to bind host port because it's already in use (see WithFixedHostPort()).
+	ErrPortInUse = errors.New("host port is already in use")
+	// ErrStartupTimeout - occurs when the container doesn't start in time (see WithStartupTimeout(), StartupTimeoutError).
+	ErrStartupTimeout = errors.New("container startup timeout exceeded")
+	// ErrDockerUnavailable - occurs when docker daemon doesn't respond (see NewPoolWithOptions()).
+	ErrDockerUnavailable = errors.New("docker is unavailable")
+	// ErrImageNotFound - occurs when the image doesn't exist locally and can't be pulled from the registry.
+	ErrImageNotFound = errors.New("image not found")
+	// ErrReadinessFailed - occurs when the container doesn't pass the readiness check (see WithRetry()).
+	ErrReadinessFailed = errors.New("container readiness check failed")
 )
 
 type (
@@ -43,7 +62,8 @@ type (
 	PrivatePort = string
 
 	// RetryOperation is an exponential backoff retry operation. You can use it to wait for e.g. mysql to boot up.
-	RetryOperation func(ctx context.Context, container *dockertest.Resource) (err error)
+	//   - container is the same *Container that is passed to hooks (see Hook) and returned by Run.
+	RetryOperation func(ctx context.Context, container *Container) (err error)
 )
 
 // NetJoinHostPort - combines ip and port into a network address of the form "host:port".
@@ -54,6 +74,19 @@ func (e APIEndpoint) NetJoinHostPort() string {
 // Pool with docker client.
 type Pool struct {
 	Pool *dockertest.Pool
+
+	// runDefaults - options applied to each Run before the options of the call (see WithDefaults()).
+	runDefaults []RunOption
+	// buildDefaults - options applied to each Build before the options of the call (see WithBuildDefaults()).
+	buildDefaults []BuildOption
+	// logger - logger for structured events of the pool operations (see WithLogger()).
+	logger *slog.Logger
+	// instrumentation - receives operations of the pool for tracing and metrics (see WithInstrumentation()).
+	instrumentation Instrumentation
+	// maxWait - max elapsed time of the readiness retries, 0 - not limited (see PoolOptions.MaxWait).
+	maxWait time.Duration
+	// stats - aggregated StartupStats of the containers, shared by copies of the pool (see Stats()).
+	stats *poolStats
 }
 
 func NewPool(endpoint string) (Pool, error) {
@@ -62,7 +95,7 @@ func NewPool(endpoint string) (Pool, error) {
 		return Pool{}, err //nolint:wrapcheck
 	}
 
-	return Pool{Pool: pool}, nil
+	return newPool(pool), nil
 }
 
 func MustNewPool(endpoint string) Pool {
@@ -74,6 +107,54 @@ func MustNewPool(endpoint string) Pool {
 	return pool
 }
 
+// NewPoolWithClient - creates Pool with already configured docker client
+// (e.g. client of the dockerfake.Server for unit tests without docker daemon).
+func NewPoolWithClient(client *docker.Client) Pool {
+	return newPool(&dockertest.Pool{Client: client, MaxWait: defaultPoolMaxWait})
+}
+
+// WithDefaults - returns derived Pool that applies runOpts to each Run
+// after the default options and before the options passed to Run.
+//   - Defaults of the parent Pool are kept and applied before runOpts.
+//   - The parent Pool isn't changed.
+//
+// Example usage:
+//
+//	pool = pool.WithDefaults(WithContainerLabels(map[string]string{"team": "billing"}), WithExpiry(time.Hour))
+//	container, err := pool.Run(ctx, "redis", WithExpiry(time.Minute)) // with team label and 1m expiry
+func (p Pool) WithDefaults(runOpts ...RunOption) Pool {
+	p.runDefaults = append(slices.Clip(p.runDefaults), runOpts...)
+	return p
+}
+
+// WithBuildDefaults - returns derived Pool that applies buildOpts to each Build and BuildAndGet
+// after the default options and before the options passed to the call. See [Pool.WithDefaults].
+func (p Pool) WithBuildDefaults(buildOpts ...BuildOption) Pool {
+	p.buildDefaults = append(slices.Clip(p.buildDefaults), buildOpts...)
+	return p
+}
+
+// WithoutDefaults - returns derived Pool without run and build defaults (see WithDefaults(), WithBuildDefaults()).
+// Client, logger, instrumentation and stats of the parent Pool are kept.
+// Useful for auxiliary containers that must not inherit defaults of the caller (e.g. sidecars).
+func (p Pool) WithoutDefaults() Pool {
+	p.runDefaults = nil
+	p.buildDefaults = nil
+	return p
+}
+
+func newPool(pool *dockertest.Pool) Pool {
+	return Pool{
+		Pool:            pool,
+		runDefaults:     nil,
+		buildDefaults:   nil,
+		logger:          nil,
+		instrumentation: nil,
+		maxWait:         0,
+		stats:           newPoolStats(),
+	}
+}
+
 // GetAPIEndpoints - provides you APIEndpoint by each privatePort (port inside the container).
 func GetAPIEndpoints(container *dockertest.Resource) (endpointByPrivatePort map[PrivatePort]APIEndpoint) {
 	mapping := container.Container.NetworkSettings.PortMappingAPI()
@@ -82,7 +163,7 @@ func GetAPIEndpoints(container *dockertest.Resource) (endpointByPrivatePort map[
 	// linux
 	// access by container ip and private (container) port
 	// accessible inside and outside container
-	host := container.Container.NetworkSettings.Networks["bridge"].IPAddress // container ip
+	host := containerIP(container.Container) // container ip
 	getPort := func(apiPort docker.APIPort) string { return strconv.Itoa(int(apiPort.PrivatePort)) }
 	// host = linuxLocalhost
 
@@ -104,13 +185,67 @@ func GetAPIEndpoints(container *dockertest.Resource) (endpointByPrivatePort map[
 	return endpointByPrivatePort
 }
 
+// GetHostEndpoints - provides you APIEndpoint accessible from the host (localhost and public port)
+// by each privatePort that has host port binding (see WithRandomHostPort(), WithFixedHostPort()).
+//   - Use it when you need stable url from the host (e.g. to pass it to the application under test).
+//   - Ports without host port binding are skipped.
+func GetHostEndpoints(container *dockertest.Resource) (endpointByPrivatePort map[PrivatePort]APIEndpoint) {
+	mapping := container.Container.NetworkSettings.PortMappingAPI()
+	endpointByPrivatePort = make(map[PrivatePort]APIEndpoint, len(mapping))
+
+	for _, apiPort := range mapping {
+		if apiPort.PublicPort == 0 {
+			continue
+		}
+
+		privatePort := strconv.Itoa(int(apiPort.PrivatePort))
+
+		// prefer ipv4 binding if port is bound to both ipv4 and ipv6
+		if _, ok := endpointByPrivatePort[privatePort]; ok && strings.Contains(apiPort.IP, ":") {
+			continue
+		}
+
+		host := apiPort.IP
+		if host == "" || net.ParseIP(host).IsUnspecified() {
+			host = macOSLocalhost
+		}
+
+		endpointByPrivatePort[privatePort] = APIEndpoint{
+			IP:   host,
+			Port: strconv.Itoa(int(apiPort.PublicPort)),
+		}
+	}
+
+	return endpointByPrivatePort
+}
+
+// containerIP - returns container ip in default "bridge" network
+// or in the first (by name) network if there is no bridge.
+func containerIP(container *docker.Container) string {
+	if container.NetworkSettings == nil {
+		return ""
+	}
+
+	if bridge, ok := container.NetworkSettings.Networks["bridge"]; ok && bridge.IPAddress != "" {
+		return bridge.IPAddress
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(container.NetworkSettings.Networks)) {
+		if ip := container.NetworkSettings.Networks[name].IPAddress; ip != "" {
+			return ip
+		}
+	}
+
+	return container.NetworkSettings.IPAddress
+}
+
 func (p Pool) inspectImageByUUID(ctx context.Context, imageUUID string) (image *docker.Image, err error) {
 	foundedImage, err := p.findImageByUUID(ctx, imageUUID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to findImageByUUID: %w", err)
 	}
 
-	return p.Pool.Client.InspectImage(foundedImage.ID) //nolint:wrapcheck
+	return p.inspectImage(ctx, foundedImage.ID)
 }
 
 func (p Pool) findImageByUUID(ctx context.Context, imageUUID string) (image docker.APIImages, err error) {
@@ -135,6 +270,107 @@ func (p Pool) findImageByUUID(ctx context.Context, imageUUID string) (image dock
 	return imageList[0], nil
 }
 
+// callWithContext - runs the docker call that doesn't support context, returns ctx.Err() if ctx is done first.
+//   - The call isn't interrupted: if ctx is done first, its goroutine leaks until the call returns
+//     and the result is discarded. Use it only for read-only calls without ctx alternative
+//     (e.g. dockertest constructors of Network and Resource bound to the pool), use dockerRequest otherwise.
+func callWithContext[T any](ctx context.Context, call func() (T, error)) (result T, err error) {
+	err = ctx.Err()
+	if err != nil {
+		return result, err //nolint:wrapcheck
+	}
+
+	type callResult struct {
+		value T
+		err   error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		value, err := call()
+		done <- callResult{value: value, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return result, ctx.Err() //nolint:wrapcheck
+	case callResult := <-done:
+		return callResult.value, callResult.err
+	}
+}
+
+// dockerRequest - sends request to the docker daemon with ctx, for the calls that docker client
+// doesn't support with ctx (e.g. PauseContainer) or without required parameters (e.g. stop signal).
+//   - apiVersion - version prefix of the path, empty - the latest API version of the daemon.
+//   - Decodes json response to result if it isn't nil.
+//   - Returns *docker.Error if the daemon responds with error status.
+func dockerRequest(
+	ctx context.Context, client *docker.Client, method, apiVersion, path string, query url.Values, result any,
+) (err error) {
+	rawEndpoint := client.Endpoint()
+	if !strings.Contains(rawEndpoint, "://") {
+		rawEndpoint = "tcp://" + rawEndpoint
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to parse endpoint `%s`: %w", rawEndpoint, err)
+	}
+
+	switch endpoint.Scheme {
+	case "unix", "npipe":
+		// host isn't used - transport of the client dials the socket
+		endpoint = &url.URL{Scheme: "http", Host: "unix.sock"} //nolint:exhaustruct
+	case "tcp":
+		endpoint.Scheme = "http"
+		if client.TLSConfig != nil {
+			endpoint.Scheme = "https"
+		}
+	}
+
+	if apiVersion != "" {
+		path = "/v" + apiVersion + path
+	}
+	endpoint = endpoint.JoinPath(path)
+	endpoint.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to NewRequest: %w", err)
+	}
+
+	response, err := client.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to %s `%s`: %w", method, path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(response.Body)
+		var errorBody struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &errorBody) != nil {
+			errorBody.Message = string(body)
+		}
+
+		return &docker.Error{Status: response.StatusCode, Message: errorBody.Message}
+	}
+
+	if result != nil {
+		err = json.NewDecoder(response.Body).Decode(result)
+		if err != nil {
+			return fmt.Errorf("failed to decode response of `%s`: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// isDockerErrorStatus - true if err is the docker API error with the status.
+func isDockerErrorStatus(err error, status int) bool {
+	var dockerErr *docker.Error
+	return errors.As(err, &dockerErr) && dockerErr.Status == status
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 
 	"github.com/kiteggrad/tcontainer"
@@ -20,8 +19,8 @@ func ExamplePool_Run() {
 
 	// define function to check the server is ready
 	url := ""
-	pingServerRetry := func(_ context.Context, container *dockertest.Resource) (err error) {
-		url = "http://" + tcontainer.GetAPIEndpoints(container)[containerAPIPort].NetJoinHostPort()
+	pingServerRetry := func(_ context.Context, container *tcontainer.Container) (err error) {
+		url = "http://" + tcontainer.GetAPIEndpoints(container.Resource)[containerAPIPort].NetJoinHostPort()
 
 		resp, err := http.Get(url)
 		if err != nil {
@@ -3,8 +3,14 @@ package tcontainer
 import (
 	"context"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
 	"testing"
 
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 )
 
@@ -16,3 +22,53 @@ func TestMain(m *testing.M) {
 
 	goleak.VerifyTestMain(m)
 }
+
+func Test_dockerRequest(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	// docker daemon on unix socket
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(err)
+
+	var requestURL *url.URL
+	server := &http.Server{ //nolint:exhaustruct,gosec
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestURL = r.URL
+			switch r.URL.Path {
+			case "/v1.42/containers/id/stop":
+				w.WriteHeader(http.StatusNoContent)
+			case "/images/busybox:latest/json":
+				_, _ = w.Write([]byte(`{"Id": "sha256:busybox"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message": "not found"}`))
+			}
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { require.NoError(server.Close()) })
+
+	client, err := docker.NewClient("unix://" + socket)
+	require.NoError(err)
+
+	err = dockerRequest(ctx, client, http.MethodPost, "1.42", "/containers/id/stop", url.Values{"t": {"10"}}, nil)
+	require.NoError(err)
+	require.Equal("10", requestURL.Query().Get("t"))
+
+	var image docker.Image
+	err = dockerRequest(ctx, client, http.MethodGet, "", "/images/busybox:latest/json", nil, &image)
+	require.NoError(err)
+	require.Equal("sha256:busybox", image.ID)
+
+	err = dockerRequest(ctx, client, http.MethodPost, "", "/containers/missing/pause", nil, nil)
+	require.True(isDockerErrorStatus(err, http.StatusNotFound))
+	require.ErrorContains(err, "not found")
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = dockerRequest(canceledCtx, client, http.MethodPost, "", "/containers/id/pause", nil, nil)
+	require.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,199 @@
+// Package tcontainerotel - OpenTelemetry implementation of the tcontainer.Instrumentation.
+//
+// Operations of the pool (Run, Build, Prune and startup phases of the Run) are recorded as spans,
+// startup phases are children of the Run span. Metrics:
+//   - `tcontainer.operation.duration` - histogram of the duration of the operations in seconds
+//     (by `tcontainer.operation`, `tcontainer.image` and `error`),
+//     e.g. startup latency is duration of `tcontainer.run`.
+//   - `tcontainer.retry.attempts` - counter of the failed readiness check attempts.
+//   - `tcontainer.run.containers` - counter of the containers returned by Run
+//     by `tcontainer.container.origin` (created, reused, repaired, recreated) - reuse hits vs recreates.
+//
+// Example usage:
+//
+//	instrumentation, err := tcontainerotel.New(tcontainerotel.WithTracerProvider(tracerProvider))
+//	pool = pool.WithInstrumentation(instrumentation)
+package tcontainerotel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kiteggrad/tcontainer"
+)
+
+// ScopeName - instrumentation scope of the tracer and the meter.
+const ScopeName = "github.com/kiteggrad/tcontainer"
+
+// Attribute keys of the spans and the metrics.
+const (
+	AttributeOperation       = attribute.Key("tcontainer.operation")
+	AttributeContainerName   = attribute.Key("tcontainer.container.name")
+	AttributeContainerID     = attribute.Key("tcontainer.container.id")
+	AttributeContainerOrigin = attribute.Key("tcontainer.container.origin")
+	AttributeImage           = attribute.Key("tcontainer.image")
+	AttributeRetryAttempt    = attribute.Key("tcontainer.retry.attempt")
+	AttributeError           = attribute.Key("error")
+)
+
+var _ tcontainer.Instrumentation = (*Instrumentation)(nil)
+
+type (
+	// Options for New function.
+	Options struct {
+		// TracerProvider - provider of the tracer, global provider (otel.GetTracerProvider()) by default.
+		TracerProvider trace.TracerProvider
+		// MeterProvider - provider of the meter, global provider (otel.GetMeterProvider()) by default.
+		MeterProvider metric.MeterProvider
+	}
+
+	// Option - option for New function.
+	Option func(options *Options) (err error)
+
+	// Instrumentation - tcontainer.Instrumentation that records spans and metrics by OpenTelemetry.
+	Instrumentation struct {
+		tracer trace.Tracer
+
+		duration      metric.Float64Histogram
+		retryAttempts metric.Int64Counter
+		containers    metric.Int64Counter
+	}
+)
+
+// WithTracerProvider - record spans by the tracerProvider.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(options *Options) (err error) {
+		if tracerProvider == nil {
+			return fmt.Errorf("%w: tracer provider is required", tcontainer.ErrInvalidOptions)
+		}
+
+		options.TracerProvider = tracerProvider
+
+		return nil
+	}
+}
+
+// WithMeterProvider - record metrics by the meterProvider.
+func WithMeterProvider(meterProvider metric.MeterProvider) Option {
+	return func(options *Options) (err error) {
+		if meterProvider == nil {
+			return fmt.Errorf("%w: meter provider is required", tcontainer.ErrInvalidOptions)
+		}
+
+		options.MeterProvider = meterProvider
+
+		return nil
+	}
+}
+
+// New - creates Instrumentation, use it by tcontainer.Pool.WithInstrumentation().
+func New(customOpts ...Option) (instrumentation *Instrumentation, err error) {
+	options := Options{TracerProvider: otel.GetTracerProvider(), MeterProvider: otel.GetMeterProvider()}
+	for _, customOpt := range customOpts {
+		err = customOpt(&options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	meter := options.MeterProvider.Meter(ScopeName)
+	instrumentation = &Instrumentation{
+		tracer:        options.TracerProvider.Tracer(ScopeName),
+		duration:      nil,
+		retryAttempts: nil,
+		containers:    nil,
+	}
+
+	instrumentation.duration, err = meter.Float64Histogram("tcontainer.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the tcontainer operations."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create duration histogram: %w", err)
+	}
+
+	instrumentation.retryAttempts, err = meter.Int64Counter("tcontainer.retry.attempts",
+		metric.WithUnit("{attempt}"),
+		metric.WithDescription("Failed attempts of the container readiness check."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create retry attempts counter: %w", err)
+	}
+
+	instrumentation.containers, err = meter.Int64Counter("tcontainer.run.containers",
+		metric.WithUnit("{container}"),
+		metric.WithDescription("Containers returned by Run by origin (created, reused, repaired, recreated)."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create containers counter: %w", err)
+	}
+
+	return instrumentation, nil
+}
+
+// Start - starts span of the operation, see tcontainer.Instrumentation.
+func (i *Instrumentation) Start(
+	ctx context.Context, operation tcontainer.Operation, info tcontainer.OperationInfo,
+) (opCtx context.Context, end tcontainer.OperationEnd) {
+	start := time.Now()
+
+	ctx, span := i.tracer.Start(ctx, string(operation),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(infoAttributes(info)...),
+	)
+
+	return ctx, func(result tcontainer.OperationResult) {
+		defer span.End()
+
+		if result.ContainerID != "" {
+			span.SetAttributes(AttributeContainerID.String(result.ContainerID))
+		}
+		if result.Origin != "" {
+			span.SetAttributes(AttributeContainerOrigin.String(string(result.Origin)))
+		}
+		if result.Err != nil {
+			span.RecordError(result.Err)
+			span.SetStatus(codes.Error, result.Err.Error())
+		}
+
+		// only low cardinality attributes for metrics (container name and ID are unique for each test)
+		i.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+			AttributeOperation.String(string(operation)),
+			AttributeImage.String(info.Image),
+			AttributeError.Bool(result.Err != nil),
+		))
+
+		if operation == tcontainer.OperationRun && result.Err == nil {
+			i.containers.Add(ctx, 1, metric.WithAttributes(AttributeContainerOrigin.String(string(result.Origin))))
+		}
+	}
+}
+
+// RetryAttempt - records failed attempt as event of the current span, see tcontainer.Instrumentation.
+func (i *Instrumentation) RetryAttempt(ctx context.Context, info tcontainer.OperationInfo, attempt int, err error) {
+	trace.SpanFromContext(ctx).AddEvent("retry attempt failed", trace.WithAttributes(
+		AttributeRetryAttempt.Int(attempt),
+		attribute.String("exception.message", err.Error()),
+	))
+
+	i.retryAttempts.Add(ctx, 1, metric.WithAttributes(AttributeImage.String(info.Image)))
+}
+
+// infoAttributes - returns non-empty attributes of the operation.
+func infoAttributes(info tcontainer.OperationInfo) (attrs []attribute.KeyValue) {
+	if info.ContainerName != "" {
+		attrs = append(attrs, AttributeContainerName.String(info.ContainerName))
+	}
+	if info.Image != "" {
+		attrs = append(attrs, AttributeImage.String(info.Image))
+	}
+
+	return attrs
+}
@@ -0,0 +1,164 @@
+package tcontainerotel_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/kiteggrad/tcontainer"
+	"github.com/kiteggrad/tcontainer/dockerfake"
+	"github.com/kiteggrad/tcontainer/tcontainerotel"
+)
+
+func Test_Instrumentation(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	spans := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	metrics := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(metrics))
+
+	instrumentation, err := tcontainerotel.New(
+		tcontainerotel.WithTracerProvider(tracerProvider),
+		tcontainerotel.WithMeterProvider(meterProvider),
+	)
+	require.NoError(err)
+
+	server := dockerfake.NewServer()
+	t.Cleanup(server.Close)
+	pool := tcontainer.NewPoolWithClient(server.Client()).WithInstrumentation(instrumentation)
+
+	attempt := 0
+	retry := tcontainer.WithRetry(func(context.Context, *tcontainer.Container) error {
+		attempt++
+		if attempt < 3 {
+			return errors.New("not ready")
+		}
+		return nil
+	}, backoff.NewConstantBackOff(time.Millisecond))
+
+	container, err := pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"), retry)
+	require.NoError(err)
+	_, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"), tcontainer.WithReuse(false))
+	require.NoError(err)
+	_, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"))
+	require.ErrorIs(err, tcontainer.ErrContainerAlreadyExists)
+
+	require.NoError(pool.Build(ctx, tcontainer.WithContextDir("../internal/testing"), tcontainer.WithDockerfile("Dockerfile.test")))
+	require.NoError(pool.Prune(ctx))
+
+	// spans
+	byName := map[string][]tracetest.SpanStub{}
+	for _, span := range spans.GetSpans() {
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+	require.Len(byName["tcontainer.run"], 3)
+	require.Len(byName["tcontainer.build"], 1)
+	require.Len(byName["tcontainer.prune"], 1)
+
+	run := byName["tcontainer.run"][0]
+	require.Contains(run.Attributes, tcontainerotel.AttributeContainerName.String("app"))
+	require.Contains(run.Attributes, tcontainerotel.AttributeImage.String("busybox:latest"))
+	require.Contains(run.Attributes, tcontainerotel.AttributeContainerID.String(container.Container.ID))
+	require.Contains(run.Attributes, tcontainerotel.AttributeContainerOrigin.String(string(tcontainer.ContainerOriginCreated)))
+	require.Equal(codes.Error, byName["tcontainer.run"][2].Status.Code)
+
+	for _, phase := range []string{"tcontainer.pull", "tcontainer.create", "tcontainer.start", "tcontainer.ready"} {
+		require.NotEmpty(byName[phase], phase)
+		require.Equal(run.SpanContext.SpanID(), byName[phase][0].Parent.SpanID(), phase)
+	}
+	require.Len(byName["tcontainer.ready"][0].Events, 2)
+
+	// metrics
+	var data metricdata.ResourceMetrics
+	require.NoError(metrics.Collect(ctx, &data))
+	require.Len(data.ScopeMetrics, 1)
+
+	byMetric := map[string]metricdata.Aggregation{}
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		byMetric[m.Name] = m.Data
+	}
+
+	duration, ok := byMetric["tcontainer.operation.duration"].(metricdata.Histogram[float64])
+	require.True(ok)
+	runs := uint64(0)
+	for _, point := range duration.DataPoints {
+		if operation, _ := point.Attributes.Value(tcontainerotel.AttributeOperation); operation.AsString() == "tcontainer.run" {
+			runs += point.Count
+		}
+	}
+	require.Equal(uint64(3), runs)
+
+	retries, ok := byMetric["tcontainer.retry.attempts"].(metricdata.Sum[int64])
+	require.True(ok)
+	require.Len(retries.DataPoints, 1)
+	require.Equal(int64(2), retries.DataPoints[0].Value)
+
+	containers, ok := byMetric["tcontainer.run.containers"].(metricdata.Sum[int64])
+	require.True(ok)
+	byOrigin := map[string]int64{}
+	for _, point := range containers.DataPoints {
+		origin, _ := point.Attributes.Value(tcontainerotel.AttributeContainerOrigin)
+		byOrigin[origin.AsString()] = point.Value
+	}
+	require.Equal(map[string]int64{
+		string(tcontainer.ContainerOriginCreated): 1,
+		string(tcontainer.ContainerOriginReused):  1,
+	}, byOrigin)
+}
+
+func Test_Instrumentation_PruneError(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	spans := tracetest.NewInMemoryExporter()
+	instrumentation, err := tcontainerotel.New(
+		tcontainerotel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))),
+	)
+	require.NoError(err)
+
+	server := dockerfake.NewServer()
+	t.Cleanup(server.Close)
+	pool := tcontainer.NewPoolWithClient(server.Client()).WithInstrumentation(instrumentation)
+
+	_, err = pool.Run(ctx, "busybox", tcontainer.WithContainerName("app"))
+	require.NoError(err)
+	server.Fail(dockerfake.Failure{Method: http.MethodDelete, Path: `^/containers/`, Times: 1})
+	require.Error(pool.Prune(ctx))
+
+	var pruneSpans []tracetest.SpanStub
+	for _, span := range spans.GetSpans() {
+		if span.Name == "tcontainer.prune" {
+			pruneSpans = append(pruneSpans, span)
+		}
+	}
+	require.Len(pruneSpans, 1)
+	require.Equal(codes.Error, pruneSpans[0].Status.Code)
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := tcontainerotel.New(tcontainerotel.WithTracerProvider(nil))
+	require.ErrorIs(t, err, tcontainer.ErrInvalidOptions)
+
+	_, err = tcontainerotel.New(tcontainerotel.WithMeterProvider(nil))
+	require.ErrorIs(t, err, tcontainer.ErrInvalidOptions)
+
+	instrumentation, err := tcontainerotel.New()
+	require.NoError(t, err)
+	require.NotNil(t, instrumentation)
+}